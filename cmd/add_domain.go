@@ -2,16 +2,81 @@ package cmd
 
 import (
 	"fmt"
+	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	withCache          bool
+	withOtel           bool
+	idempotentCreate   bool
+	transport          string
+	jsonNaming         string
+	timestampFormat    string
+	fieldsSpec         string
+	flatDomain         bool
+	cursorPagination   bool
+	linkPagination     bool
+	queryBuilder       bool
+	withListParams     bool
+	withBulk           bool
+	withExport         bool
+	withFeatureFlags   bool
+	withSwag           bool
+	withAuthz          bool
+	withSanitize       bool
+	withBindMiddleware bool
+	noTimestamps       bool
+	repoErrors         bool
+	withPatch          bool
+	belongsTo          []string
+	hasMany            []string
+	continueOnError    bool
+	runTidy            bool
+	migrations         string
+	withHandlerTests   bool
+	withUserRateLimit  bool
+)
+
+// domainOptions are the settings of the domain being added that addDomain
+// derives from its flags and the project, passed to the generators
+type domainOptions struct {
+	// QueryBuilder is set by --query-builder and by --list-params, whose
+	// params struct is turned into the builder's query
+	QueryBuilder bool
+	// Relations are the associations resolved from --belongs-to and --has-many
+	Relations []relationSpec
+	// ContextTransactions is set in projects initialized with --tx-middleware,
+	// whose repositories use the transaction carried by the request context
+	ContextTransactions bool
+	// ContextLoggers is set in projects initialized with --request-logging,
+	// whose handlers log failures on the request-scoped logger
+	ContextLoggers bool
+	// DomainEvents is set in projects initialized with --event-bus, whose
+	// services publish their domain events to an injected events.EventBus
+	DomainEvents bool
+	// Handlerless is set in projects without a web framework, such as those
+	// initialized with --minimal, whose domains get no handler
+	Handlerless bool
+	// ChiHandlers is set in projects initialized with --handler chi, whose
+	// domains get net/http handlers registered on a chi.Router
+	ChiHandlers bool
+	// MemoryRepository is set in handlerless projects without gorm, whose
+	// repositories keep models in memory
+	MemoryRepository bool
+}
+
 var addDomainCmd = &cobra.Command{
-	Use:   "add-domain [domain-name]",
-	Short: "Add a new domain to the GEAR project",
+	Use:   "add-domain [domain-name...]",
+	Short: "Add new domains to the GEAR project",
 	Long: `Add a new domain following GEAR architecture patterns.
 
 Creates a complete domain structure with:
@@ -19,15 +84,222 @@ Creates a complete domain structure with:
 - Service implementation (unexported struct)
 - Repository interface and implementation
 - Model definitions with response objects
-- Handler with route registration
-- Optional test files`,
-	Args: cobra.ExactArgs(1),
+- Handler with route registration (gin, or net/http handlers on a chi
+  router in --handler chi projects, which support --with-patch and --bulk)
+- Optional test files
+- Optional caching repository decorator (--with-cache)
+- Optional OpenTelemetry spans around service methods (--otel)
+- Optional Idempotency-Key replay on Create (--idempotent-create)
+- Optional keyset pagination of List with ?after= and ?limit= (--cursor-pagination)
+- Optional offset pagination of List with ?offset= and ?limit=, answering
+  X-Total-Count and a Link header to the next, prev and last pages (--link-pagination)
+- Optional typed query builder filtering, ordering and paging List from its
+  query parameters (--query-builder)
+- Optional <Domain>ListParams struct binding and validating the query
+  parameters of List (--list-params)
+- Optional bulk create and delete endpoints (--bulk)
+- Optional CSV or NDJSON export streaming every row in batches (--with-export)
+- Optional feature flags gating each service method (--feature-flags)
+- Optional swaggo annotations on each handler method (--swag)
+- Optional authorization check before each handler operation (--authz)
+- Optional per-user rate limit of the domain's routes, backed by an
+  injected store (--with-ratelimit-per-user)
+- Optional trimming and size capping of string fields (--sanitize)
+- Optional request binding and validation middleware (--with-validation-middleware)
+- Optional omission of the CreatedAt/UpdatedAt timestamps (--no-timestamps)
+- Optional fixed encoding of the response timestamps (--timestamp-format)
+- Optional repositories returning domain errors (--repo-errors)
+- Optional associations with other domains (--belongs-to, --has-many)
+- Optional partial updates with PATCH /<domain>s/:id (--with-patch)
+- snake_case or camelCase JSON keys (--json-naming)
+- Optional versioned SQL migrations of the domain's table (--migrations sql),
+  or database.AutoMigrate warning about schema drift in development
+  (--migrations automigrate)
+- Optional httptest tests of the handler against a mock service (--with-handler-tests)
+- Layer type names from the naming templates of .gearrc, such as IUserService
+  (--interface-name, --struct-name, --constructor-name)
+
+Model fields come from --fields as name:type[:modifiers]. Types are string,
+text, int, int64, uint, float, bool, time and uuid. Modifiers are required,
+unique, email, min=N, max=N and size=N; min/max bound numbers by value and
+strings by length. Field names must be unique ignoring case and
+underscores, and id, created_at and updated_at are declared by every model.
+The generated Validate() checks the modifiers and the service calls it
+before Create and Update:
+
+  gear add-domain user --fields "email:string:required,unique,email,age:int:min=0,max=120"
+
+An enum(a,b,c) type declares a string type named after the model and field
+with one constant per value and a Valid() method; the column is a varchar,
+responses serialize the value as its string and Validate() rejects values
+outside the list:
+
+  gear add-domain user --fields "status:enum(active,inactive,pending):required"
+
+With --sanitize the model also gets a Sanitize() method trimming the
+surrounding whitespace of string fields, which the service calls before
+Validate(), and Validate() rejects strings longer than their size (255
+unless set with size=N).
+
+With --no-timestamps the model, response and ToResponse() have no
+CreatedAt/UpdatedAt, for lookup tables such as countries or statuses. It
+cannot be combined with --cursor-pagination, which pages on created_at.
+
+Responses encode CreatedAt/UpdatedAt as time.Time does, in RFC 3339 with as
+many fractional digits as the stored time has. --timestamp-format rfc3339
+encodes them in UTC to the second and --timestamp-format epoch-millis as
+milliseconds since the Unix epoch, through the types of internal/timestamp.
+
+By default the repository returns gorm errors and the service translates
+them, mapping gorm.ErrRecordNotFound to ErrNotFound. With --repo-errors the
+repository owns the translation and returns *errors.Error values directly,
+so the service passes them on unchanged and no longer imports gorm.
+
+--belongs-to organization adds a required organization_id foreign key and
+an Organization association; --has-many post adds a Posts association,
+keyed by the UserID field the post model must already declare. GetByID and
+List preload the associations (db.Preload("Organization")) and the
+response nests their responses. The related domains must exist, and a
+relationship is declared from one side only, since two models importing
+each other would be an import cycle:
+
+  gear add-domain organization
+  gear add-domain post --fields "title:string:required,user_id:uuid:required"
+  gear add-domain user --belongs-to organization --has-many post
+
+With --with-patch the domain also gets PATCH /<domain>s/:id. Its body binds
+into a <Domain>Patch of pointer fields, so omitted fields stay nil and keep
+their stored value instead of being zeroed as with PUT. The service's
+Patch<Domain> applies the provided fields to the stored record, validates
+it and has the repository write only those columns, with gorm's
+Select(columns).Updates.
+
+With --transport websocket the domain gets a message model, a service
+handling inbound messages and a WebSocket handler with a connection hub
+broadcasting the service's replies, instead of the REST CRUD layers.
+
+With --flat the layers are generated into a single package,
+pkg/<domain>/<domain>.go, still interface-first. "// gear:section" comments
+mark where each layer starts so gear validate applies the same rules.
+
+Several domains can be added at once, each generated in turn with the same
+flags. The first failure stops the remaining domains unless
+--continue-on-error is set, and a summary lists the domains added, failed
+and skipped:
+
+  gear add-domain user order product
+
+Afterwards the imports of the generated files are looked up in go.mod and
+go.sum. Packages they do not provide yet, such as github.com/google/uuid in
+a fresh project, are listed with the go get commands adding them; --tidy
+runs go mod tidy instead.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		domainName := args[0]
-		return addDomain(domainName)
+		return addDomains(args)
 	},
 }
 
+func init() {
+	addDomainCmd.Flags().BoolVar(&withCache, "with-cache", false, "Generate a caching repository decorator for GetByID")
+	addDomainCmd.Flags().BoolVar(&withOtel, "otel", false, "Wrap service methods in OpenTelemetry spans using an injected trace.Tracer")
+	addDomainCmd.Flags().BoolVar(&idempotentCreate, "idempotent-create", false, "Replay Create responses for a repeated Idempotency-Key header from an injected store")
+	addDomainCmd.Flags().StringVar(&transport, "transport", "http", "Handler transport (http|websocket)")
+	addDomainCmd.Flags().StringVar(&jsonNaming, "json-naming", "snake_case", "JSON key convention of responses and request bodies (snake_case|camelCase)")
+	addDomainCmd.Flags().StringVar(&timestampFormat, "timestamp-format", "rfc3339nano", "JSON encoding of CreatedAt and UpdatedAt in responses (rfc3339nano|rfc3339|epoch-millis)")
+	addDomainCmd.Flags().StringVar(&interfaceNameTemplate, "interface-name", "", "Template of layer interface names, e.g. I{Domain}{Layer} (overrides naming.interface of .gearrc)")
+	addDomainCmd.Flags().StringVar(&structNameTemplate, "struct-name", "", "Template of layer struct names, e.g. {domain}{Layer}Impl (overrides naming.struct of .gearrc)")
+	addDomainCmd.Flags().StringVar(&constructorNameTemplate, "constructor-name", "", "Template of layer constructor names, e.g. Make{Domain}{Layer} (overrides naming.constructor of .gearrc)")
+	addDomainCmd.Flags().StringVar(&fieldsSpec, "fields", defaultFields, "Model fields as name:type[:modifiers], comma separated")
+	addDomainCmd.Flags().BoolVar(&withBulk, "bulk", false, "Generate POST and DELETE /<domain>s/bulk endpoints backed by batch repository methods")
+	addDomainCmd.Flags().BoolVar(&withExport, "with-export", false, "Generate GET /<domain>s/export streaming every row as CSV, or NDJSON for Accept: application/x-ndjson, read from the repository in batches")
+	addDomainCmd.Flags().BoolVar(&cursorPagination, "cursor-pagination", false, "Page List with an opaque ?after= cursor (keyset on created_at, id) and ?limit=")
+	addDomainCmd.Flags().BoolVar(&linkPagination, "link-pagination", false, "Page List with ?offset= and ?limit=, answering the total in X-Total-Count and the next, prev and last pages in a Link header")
+	addDomainCmd.Flags().BoolVar(&queryBuilder, "query-builder", false, "Generate a typed <Domain>Query builder (Where<Field>, OrderBy, Paginate) that List compiles to a gorm query, with the handler mapping query parameters to it")
+	addDomainCmd.Flags().BoolVar(&withListParams, "list-params", false, "Bind the query parameters of List into a validated <Domain>ListParams struct that the service turns into a <Domain>Query (implies --query-builder)")
+	addDomainCmd.Flags().BoolVar(&withFeatureFlags, "feature-flags", false, "Gate each service method behind an injected featureflags.FeatureFlags (\"<domain>.<action>\")")
+	addDomainCmd.Flags().BoolVar(&withAuthz, "authz", false, "Check an injected authz.Authorizer (\"<domain>:<action>\") before each handler operation")
+	addDomainCmd.Flags().BoolVar(&withSanitize, "sanitize", false, "Trim string fields and reject values longer than their size before Create and Update")
+	addDomainCmd.Flags().BoolVar(&noTimestamps, "no-timestamps", false, "Omit the CreatedAt and UpdatedAt fields, e.g. for lookup tables")
+	addDomainCmd.Flags().BoolVar(&repoErrors, "repo-errors", false, "Translate gorm errors in the repository, which returns domain errors to the service")
+	addDomainCmd.Flags().BoolVar(&withPatch, "with-patch", false, "Generate a PATCH /<domain>s/:id endpoint updating only the fields present in the body")
+	addDomainCmd.Flags().StringSliceVar(&belongsTo, "belongs-to", nil, "Domains this one belongs to, adding a <domain>_id foreign key and a preloaded association")
+	addDomainCmd.Flags().StringSliceVar(&hasMany, "has-many", nil, "Domains this one has many of, adding a preloaded association keyed by their <Domain>ID field")
+	addDomainCmd.Flags().BoolVar(&withBindMiddleware, "with-validation-middleware", false, "Decode and validate request bodies in an httputil.Bind middleware; handlers read them with httputil.Body")
+	addDomainCmd.Flags().BoolVar(&withSwag, "swag", false, "Annotate handler methods with swaggo comments for swag init")
+	addDomainCmd.Flags().BoolVar(&flatDomain, "flat", false, "Generate the domain as a single package in pkg/<domain>/<domain>.go")
+	addDomainCmd.Flags().StringVar(&migrations, "migrations", "none", "Also generate the domain's table as versioned migrations/NNNN_create_<domain>s.{up,down}.sql for golang-migrate, or database.AutoMigrate logging schema drift in development (none|sql|automigrate)")
+	addDomainCmd.Flags().BoolVar(&withHandlerTests, "with-handler-tests", false, "Also generate <domain>_handler_test.go, testing each endpoint through httptest against a mock service")
+	addDomainCmd.Flags().BoolVar(&withUserRateLimit, "with-ratelimit-per-user", false, "Limit the requests of each user or API key to the domain's routes through an injected ratelimit.Store, answering 429 with Retry-After")
+	addDomainCmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "When adding several domains, go on with the others after one fails")
+	addDomainCmd.Flags().BoolVar(&runTidy, "tidy", false, "Run go mod tidy when the generated code imports packages go.mod or go.sum do not provide")
+}
+
+// addDomains adds each domain in turn and summarizes the outcome when there
+// are several. A failure stops the remaining domains unless --continue-on-error
+// is set.
+func addDomains(domainNames []string) error {
+	for i, domainName := range domainNames {
+		if slices.Contains(domainNames[:i], domainName) {
+			return fmt.Errorf("domain %s given twice", domainName)
+		}
+	}
+	if len(domainNames) == 1 {
+		if err := addDomain(domainNames[0]); err != nil {
+			return err
+		}
+		return checkDomainDependencies()
+	}
+
+	var added, skipped []string
+	var failures []error
+	for i, domainName := range domainNames {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := addDomain(domainName); err != nil {
+			fmt.Printf("❌ Domain %s failed: %v\n", domainName, err)
+			failures = append(failures, fmt.Errorf("%s: %w", domainName, err))
+			if !continueOnError {
+				skipped = domainNames[i+1:]
+				break
+			}
+			continue
+		}
+		added = append(added, domainName)
+	}
+
+	fmt.Printf("\n📦 Added %d of %d domains\n", len(added), len(domainNames))
+	if len(added) > 0 {
+		fmt.Printf("  ✅ %s\n", strings.Join(added, ", "))
+	}
+	for _, failure := range failures {
+		fmt.Printf("  ❌ %v\n", failure)
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("  ⏭️  %s (not attempted, use --continue-on-error to add them anyway)\n", strings.Join(skipped, ", "))
+	}
+
+	if len(added) > 0 {
+		if err := checkDomainDependencies(); err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to add %d of %d domains", len(failures), len(domainNames))
+	}
+	return nil
+}
+
+// checkDomainDependencies checks that go.mod and go.sum provide the packages
+// the added domains import, running go mod tidy with --tidy
+func checkDomainDependencies() error {
+	moduleName, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to read module name: %w", err)
+	}
+	return checkDependencies(moduleName, runTidy)
+}
+
 func addDomain(domainName string) error {
 	fmt.Printf("🏗️  Adding domain: %s\n", domainName)
 
@@ -36,402 +308,2610 @@ func addDomain(domainName string) error {
 		return fmt.Errorf("not in a Go project directory (go.mod not found)")
 	}
 
-	// Read module name from go.mod
-	moduleName, err := getModuleName()
+	// Read module name from go.mod
+	moduleName, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to read module name: %w", err)
+	}
+
+	if jsonNaming != "snake_case" && jsonNaming != "camelCase" {
+		return fmt.Errorf("unsupported --json-naming %q (expected snake_case or camelCase)", jsonNaming)
+	}
+	if _, ok := timestampTypes[timestampFormat]; !ok {
+		return fmt.Errorf("unsupported --timestamp-format %q (expected rfc3339nano, rfc3339 or epoch-millis)", timestampFormat)
+	}
+	if migrations != "none" && migrations != "sql" && migrations != "automigrate" {
+		return fmt.Errorf("unsupported --migrations %q (expected none, sql or automigrate)", migrations)
+	}
+
+	naming, err := loadNaming()
+	if err != nil {
+		return err
+	}
+	if err := setLayerNames(naming, domainName); err != nil {
+		return err
+	}
+
+	framework := detectHandler()
+	opts := domainOptions{
+		Handlerless: framework == "none",
+		ChiHandlers: framework == "chi",
+	}
+	opts.MemoryRepository = opts.Handlerless && detectORM() == "none"
+	for _, option := range []struct {
+		flag        string
+		set, needed bool
+		what        string
+	}{
+		{"--transport websocket", transport == "websocket", opts.Handlerless, "a web framework"},
+		{"--flat", flatDomain, opts.Handlerless, "a web framework"},
+		{"--swag", withSwag, opts.Handlerless, "a web framework"},
+		{"--authz", withAuthz, opts.Handlerless, "a web framework"},
+		{"--idempotent-create", idempotentCreate, opts.Handlerless, "a web framework"},
+		{"--with-validation-middleware", withBindMiddleware, opts.Handlerless, "a web framework"},
+		{"--with-patch", withPatch, opts.Handlerless, "a web framework"},
+		{"--with-handler-tests", withHandlerTests, opts.Handlerless, "a web framework"},
+		{"--with-ratelimit-per-user", withUserRateLimit, opts.Handlerless, "a web framework"},
+		{"--with-export", withExport, opts.Handlerless, "a web framework"},
+		{"--bulk", withBulk, opts.MemoryRepository, "an ORM"},
+		{"--with-export", withExport, opts.MemoryRepository, "an ORM"},
+		{"--migrations " + migrations, migrations != "none", opts.MemoryRepository, "an ORM"},
+		{"--cursor-pagination", cursorPagination, opts.MemoryRepository, "an ORM"},
+		{"--link-pagination", linkPagination, opts.MemoryRepository, "an ORM"},
+		{"--query-builder", queryBuilder, opts.MemoryRepository, "an ORM"},
+		{"--list-params", withListParams, opts.Handlerless, "a web framework"},
+		{"--list-params", withListParams, opts.MemoryRepository, "an ORM"},
+		{"--belongs-to", len(belongsTo) > 0, opts.MemoryRepository, "an ORM"},
+		{"--has-many", len(hasMany) > 0, opts.MemoryRepository, "an ORM"},
+	} {
+		if option.set && option.needed {
+			return fmt.Errorf("%s needs %s, which go.mod does not require", option.flag, option.what)
+		}
+	}
+
+	// The rate limiter answers with ErrTooManyRequestsInstance, which errors
+	// packages generated before TOO_MANY_REQUESTS existed lack
+	if withUserRateLimit {
+		data, err := os.ReadFile(filepath.Join("internal", "errors", "errors.go"))
+		if err == nil && !strings.Contains(string(data), "ErrTooManyRequestsInstance") {
+			return fmt.Errorf("--with-ratelimit-per-user answers with errors.ErrTooManyRequestsInstance, which internal/errors/errors.go predates (run gear upgrade first)")
+		}
+	}
+
+	switch transport {
+	case "http":
+	case "websocket":
+		if fieldsSpec != defaultFields {
+			return fmt.Errorf("--fields is not supported with --transport websocket")
+		}
+		if flatDomain {
+			return fmt.Errorf("--flat is not supported with --transport websocket")
+		}
+		if cursorPagination {
+			return fmt.Errorf("--cursor-pagination is not supported with --transport websocket")
+		}
+		if linkPagination {
+			return fmt.Errorf("--link-pagination is not supported with --transport websocket")
+		}
+		if queryBuilder {
+			return fmt.Errorf("--query-builder is not supported with --transport websocket")
+		}
+		if withListParams {
+			return fmt.Errorf("--list-params is not supported with --transport websocket")
+		}
+		if withBulk {
+			return fmt.Errorf("--bulk is not supported with --transport websocket")
+		}
+		if withExport {
+			return fmt.Errorf("--with-export is not supported with --transport websocket")
+		}
+		if withFeatureFlags {
+			return fmt.Errorf("--feature-flags is not supported with --transport websocket")
+		}
+		if withSwag {
+			return fmt.Errorf("--swag is not supported with --transport websocket")
+		}
+		if withAuthz {
+			return fmt.Errorf("--authz is not supported with --transport websocket")
+		}
+		if withUserRateLimit {
+			return fmt.Errorf("--with-ratelimit-per-user is not supported with --transport websocket")
+		}
+		if withSanitize {
+			return fmt.Errorf("--sanitize is not supported with --transport websocket")
+		}
+		if withBindMiddleware {
+			return fmt.Errorf("--with-validation-middleware is not supported with --transport websocket")
+		}
+		if noTimestamps {
+			return fmt.Errorf("--no-timestamps is not supported with --transport websocket")
+		}
+		if timestampFormat != "rfc3339nano" {
+			return fmt.Errorf("--timestamp-format is not supported with --transport websocket")
+		}
+		if repoErrors {
+			return fmt.Errorf("--repo-errors is not supported with --transport websocket")
+		}
+		if len(belongsTo) > 0 || len(hasMany) > 0 {
+			return fmt.Errorf("--belongs-to and --has-many are not supported with --transport websocket")
+		}
+		if withPatch {
+			return fmt.Errorf("--with-patch is not supported with --transport websocket")
+		}
+		if withHandlerTests {
+			return fmt.Errorf("--with-handler-tests is not supported with --transport websocket")
+		}
+		return addWebSocketDomain(domainName, moduleName)
+	default:
+		return fmt.Errorf("unsupported transport %q (expected http or websocket)", transport)
+	}
+
+	if noTimestamps && timestampFormat != "rfc3339nano" {
+		return fmt.Errorf("--timestamp-format has no timestamps to encode with --no-timestamps")
+	}
+	if noTimestamps && cursorPagination {
+		return fmt.Errorf("--no-timestamps cannot be combined with --cursor-pagination, which pages on created_at")
+	}
+	if cursorPagination && linkPagination {
+		return fmt.Errorf("--cursor-pagination and --link-pagination are mutually exclusive")
+	}
+	if queryBuilder && (cursorPagination || linkPagination) {
+		return fmt.Errorf("--query-builder pages List itself and cannot be combined with --cursor-pagination or --link-pagination")
+	}
+	if withListParams && (cursorPagination || linkPagination) {
+		return fmt.Errorf("--list-params pages List itself and cannot be combined with --cursor-pagination or --link-pagination")
+	}
+
+	if withBindMiddleware && framework != "gin" {
+		return fmt.Errorf("--with-validation-middleware is only supported for gin projects (detected %s)", framework)
+	}
+
+	if withHandlerTests && framework != "gin" {
+		return fmt.Errorf("--with-handler-tests is only supported for gin projects (detected %s)", framework)
+	}
+
+	// chi handlers cover CRUD, --with-patch and --bulk; the other handler
+	// options generate gin code
+	if opts.ChiHandlers {
+		for _, option := range []struct {
+			flag string
+			set  bool
+		}{
+			{"--swag", withSwag},
+			{"--authz", withAuthz},
+			{"--idempotent-create", idempotentCreate},
+			{"--with-ratelimit-per-user", withUserRateLimit},
+			{"--with-export", withExport},
+			{"--cursor-pagination", cursorPagination},
+			{"--link-pagination", linkPagination},
+			{"--query-builder", queryBuilder},
+			{"--list-params", withListParams},
+		} {
+			if option.set {
+				return fmt.Errorf("%s is only supported for gin projects (detected chi)", option.flag)
+			}
+		}
+	}
+
+	// The params struct is turned into the builder's query
+	opts.QueryBuilder = queryBuilder || withListParams
+
+	fields, err := parseFields(fieldsSpec)
+	if err != nil {
+		return fmt.Errorf("invalid --fields: %w", err)
+	}
+	qualifyEnums(fields, capitalize(domainName))
+	for i := range fields {
+		fields[i].Sanitize = withSanitize
+	}
+
+	opts.Relations, err = parseRelations(domainName, moduleName, belongsTo, hasMany)
+	if err != nil {
+		return err
+	}
+	fields, err = relationFields(fields, opts.Relations)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(filepath.Join("internal", "transaction", "transaction.go"))
+	opts.ContextTransactions = err == nil
+	_, err = os.Stat(filepath.Join("internal", "logging", "logging.go"))
+	opts.ContextLoggers = err == nil
+	_, err = os.Stat(filepath.Join("internal", "events", "events.go"))
+	opts.DomainEvents = err == nil
+
+	if _, err := os.Stat(filepath.Join("internal", "httputil", "params.go")); os.IsNotExist(err) && !opts.Handlerless {
+		generate := generateHTTPUtilPackage
+		if opts.ChiHandlers {
+			generate = generateChiHTTPUtilPackage
+		}
+		if err := generate(moduleName); err != nil {
+			return err
+		}
+	}
+
+	// Projects whose httputil predates respond.go get it with their next domain
+	if _, err := os.Stat(filepath.Join("internal", "httputil", "respond.go")); os.IsNotExist(err) && !opts.Handlerless {
+		if err := generateRespondHelper(moduleName); err != nil {
+			return err
+		}
+	}
+	if _, err := os.Stat(filepath.Join("internal", "httputil", "decode.go")); os.IsNotExist(err) && !opts.Handlerless {
+		if err := generateDecodeHelper(moduleName); err != nil {
+			return err
+		}
+	}
+
+	if withListParams {
+		if _, err := os.Stat(filepath.Join("internal", "httputil", "query.go")); os.IsNotExist(err) {
+			if err := generateBindQueryHelper(moduleName); err != nil {
+				return err
+			}
+		}
+	}
+
+	if withBindMiddleware {
+		if _, err := os.Stat(filepath.Join("internal", "httputil", "bind.go")); os.IsNotExist(err) {
+			if err := generateBindHelper(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if idempotentCreate {
+		if _, err := os.Stat(filepath.Join("internal", "idempotency", "idempotency.go")); os.IsNotExist(err) {
+			if err := generateIdempotencyPackage(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if timestampFormat != "rfc3339nano" {
+		if _, err := os.Stat(filepath.Join("internal", "timestamp", "timestamp.go")); os.IsNotExist(err) {
+			if err := generateTimestampPackage(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cursorPagination {
+		if _, err := os.Stat(filepath.Join("internal", "pagination", "pagination.go")); os.IsNotExist(err) {
+			if err := generatePaginationPackage(moduleName); err != nil {
+				return err
+			}
+		}
+	}
+
+	if linkPagination {
+		if _, err := os.Stat(filepath.Join("internal", "pagination", "links.go")); os.IsNotExist(err) {
+			if err := generateLinkPaginationPackage(moduleName); err != nil {
+				return err
+			}
+		}
+	}
+
+	if withFeatureFlags {
+		if _, err := os.Stat(filepath.Join("internal", "featureflags", "featureflags.go")); os.IsNotExist(err) {
+			if err := generateFeatureFlagsPackage(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if withAuthz {
+		if _, err := os.Stat(filepath.Join("internal", "authz", "authz.go")); os.IsNotExist(err) {
+			if err := generateAuthzPackage(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if withUserRateLimit {
+		if _, err := os.Stat(filepath.Join("internal", "ratelimit", "ratelimit.go")); os.IsNotExist(err) {
+			if err := generateRateLimitPackage(); err != nil {
+				return err
+			}
+		}
+	}
+
+	var migrationFiles []string
+	switch migrations {
+	case "sql":
+		if migrationFiles, err = generateMigration(domainName, fields, opts.Relations); err != nil {
+			return err
+		}
+	case "automigrate":
+		if migrationFiles, err = generateAutoMigrate(); err != nil {
+			return err
+		}
+	}
+
+	if flatDomain {
+		return addFlatDomain(domainName, moduleName, fields, opts, migrationFiles)
+	}
+
+	// Create domain directory structure
+	domainPath := filepath.Join("pkg", domainName)
+	dirs := []string{
+		filepath.Join(domainPath, "service"),
+		filepath.Join(domainPath, "repository"),
+		filepath.Join(domainPath, "model"),
+	}
+	if !opts.Handlerless {
+		dirs = append(dirs, filepath.Join(domainPath, "handler"))
+	}
+
+	if includeTests {
+		dirs = append(dirs,
+			filepath.Join(domainPath, "service", "test"),
+			filepath.Join(domainPath, "repository", "test"),
+		)
+	}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	// Generate domain files
+	if err := writeFile(filepath.Join(domainPath, "model", domainName+".go"), modelSource(domainName, moduleName, fields, opts)); err != nil {
+		return err
+	}
+
+	if opts.QueryBuilder {
+		if err := writeFile(filepath.Join(domainPath, "model", domainName+"_query.go"), querySource(domainName, moduleName, fields)); err != nil {
+			return err
+		}
+	}
+
+	repository := repositorySource(domainName, moduleName, opts)
+	if opts.MemoryRepository {
+		repository = memoryRepositorySource(domainName, moduleName)
+	}
+	if err := writeFile(filepath.Join(domainPath, "repository", domainName+"_repository.go"), repository); err != nil {
+		return err
+	}
+
+	if withCache {
+		if err := writeFile(filepath.Join(domainPath, "repository", "cached_"+domainName+"_repository.go"), cachedRepositorySource(domainName, moduleName, opts)); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFile(filepath.Join(domainPath, "service", domainName+"_service.go"), serviceSource(domainName, moduleName, opts)); err != nil {
+		return err
+	}
+
+	if !opts.Handlerless {
+		if err := writeFile(filepath.Join(domainPath, "handler", domainName+"_handler.go"), handlerSource(domainName, moduleName, fields, opts)); err != nil {
+			return err
+		}
+	}
+
+	if withHandlerTests {
+		tests, err := handlerTestSource(domainName, moduleName, fields, opts)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(domainPath, "handler", domainName+"_handler_test.go"), tests); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("✅ Domain %s added successfully!\n", domainName)
+	fmt.Printf("\nGenerated files:\n")
+	fmt.Printf("  pkg/%s/model/%s.go\n", domainName, domainName)
+	if opts.QueryBuilder {
+		fmt.Printf("  pkg/%s/model/%s_query.go\n", domainName, domainName)
+	}
+	fmt.Printf("  pkg/%s/repository/%s_repository.go\n", domainName, domainName)
+	if withCache {
+		fmt.Printf("  pkg/%s/repository/cached_%s_repository.go\n", domainName, domainName)
+	}
+	fmt.Printf("  pkg/%s/service/%s_service.go\n", domainName, domainName)
+	if !opts.Handlerless {
+		fmt.Printf("  pkg/%s/handler/%s_handler.go\n", domainName, domainName)
+	}
+	if withHandlerTests {
+		fmt.Printf("  pkg/%s/handler/%s_handler_test.go\n", domainName, domainName)
+	}
+	for _, fileName := range migrationFiles {
+		fmt.Printf("  %s\n", fileName)
+	}
+	if migrations == "automigrate" {
+		printAutoMigrateUsage("model." + capitalize(domainName))
+	}
+
+	return nil
+}
+
+func modelSource(domainName, moduleName string, fields []fieldSpec, opts domainOptions) string {
+	structName := capitalize(domainName)
+
+	modelRows := [][]string{{"ID", "uuid.UUID", "`gorm:\"type:uuid;primary_key;default:gen_random_uuid()\" json:\"-\"`"}}
+	requestRows := [][]string{}
+	responseRows := [][]string{{"ID", "uuid.UUID", "`json:\"" + jsonName("id") + "\"`"}}
+	toModelRows := [][]string{}
+	toResponseRows := [][]string{{"ID:", "u.ID,"}}
+	patchRows := [][]string{}
+	var applyPatch strings.Builder
+	for _, field := range fields {
+		patchRows = append(patchRows, []string{field.GoName, "*" + field.GoType, "`json:\"" + jsonName(field.Name) + "\"`"})
+		applyPatch.WriteString(field.patchStatement("p", "u"))
+		modelRows = append(modelRows, []string{field.GoName, field.GoType, "`" + field.gormTag() + "json:\"-\"`"})
+		requestRows = append(requestRows, []string{field.GoName, field.GoType, "`json:\"" + jsonName(field.Name) + "\"`"})
+		responseRows = append(responseRows, []string{field.GoName, field.GoType, "`json:\"" + jsonName(field.Name) + "\"`"})
+		toModelRows = append(toModelRows, []string{field.GoName + ":", "r." + field.GoName + ","})
+		toResponseRows = append(toResponseRows, []string{field.GoName + ":", "u." + field.GoName + ","})
+	}
+	for _, relation := range opts.Relations {
+		modelRows = append(modelRows, relation.modelRow(structName))
+		responseRows = append(responseRows, relation.responseRow())
+	}
+	if !noTimestamps {
+		timestampModel, timestampResponse, timestampToResponse := timestampRows()
+		modelRows = append(modelRows, timestampModel...)
+		responseRows = append(responseRows, timestampResponse...)
+		toResponseRows = append(toResponseRows, timestampToResponse...)
+	}
+
+	var checks, trims strings.Builder
+	usesEmail, usesUTF8, usesTime := false, false, !noTimestamps
+	for _, field := range fields {
+		checks.WriteString(field.validateStatements("u", jsonName(field.Name)))
+		usesEmail = usesEmail || field.Email
+		usesUTF8 = usesUTF8 || field.usesUTF8()
+		usesTime = usesTime || field.GoType == "time.Time"
+		if field.Sanitize && field.GoType == "string" {
+			trims.WriteString(field.sanitizeStatement("u"))
+		}
+	}
+
+	var imports []string
+	if usesTime {
+		imports = append(imports, `"time"`)
+	}
+	if usesEmail {
+		imports = append(imports, `"regexp"`)
+	}
+	if trims.Len() > 0 {
+		imports = append(imports, `"strings"`)
+	}
+	if usesUTF8 {
+		imports = append(imports, `"unicode/utf8"`)
+	}
+	sort.Strings(imports)
+	if len(imports) > 0 {
+		imports = append(imports, "")
+	}
+	imports = append(imports, `"github.com/google/uuid"`)
+	var moduleImports []string
+	if checks.Len() > 0 {
+		moduleImports = append(moduleImports, fmt.Sprintf(`"%s/internal/errors"`, moduleName))
+	}
+	if timestampTypes[timestampFormat] != "" && !noTimestamps {
+		moduleImports = append(moduleImports, fmt.Sprintf(`"%s/internal/timestamp"`, moduleName))
+	}
+
+	// Preloaded associations are converted to their own responses
+	toResponse := fmt.Sprintf(`	return &%[1]sResponse{
+%[2]s	}
+`, structName, alignColumns(toResponseRows, "\t\t"))
+	if len(opts.Relations) > 0 {
+		var conversions strings.Builder
+		for _, relation := range opts.Relations {
+			moduleImports = append(moduleImports, relation.Import)
+			conversions.WriteString(relation.toResponseStatement())
+		}
+		toResponse = fmt.Sprintf(`	response := &%[1]sResponse{
+%[2]s	}
+%[3]s	return response
+`, structName, alignColumns(toResponseRows, "\t\t"), conversions.String())
+	}
+	if len(moduleImports) > 0 {
+		imports = append(imports, "")
+		imports = append(imports, moduleImports...)
+	}
+
+	declarations := ""
+	if usesEmail {
+		declarations = "\n" + emailPatternDecl + "\n"
+	}
+	for _, field := range fields {
+		if len(field.Enum) > 0 {
+			declarations += field.enumDecl()
+		}
+	}
+
+	// Introspected models keep the name of the table they were read from
+	tableName := ""
+	if introspectTable != "" {
+		tableName = fmt.Sprintf(`
+// TableName maps %[1]s to the existing %[2]s table
+func (%[1]s) TableName() string {
+	return %[2]q
+}
+`, structName, introspectTable)
+	}
+
+	// With --sanitize the service trims string fields before validating them
+	sanitize := ""
+	if withSanitize {
+		sanitize = fmt.Sprintf(`
+// Sanitize trims the surrounding whitespace of the %[1]s string fields
+func (u *%[1]s) Sanitize() {
+%[2]s}
+`, structName, trims.String())
+	}
+
+	// With --with-validation-middleware, httputil.Bind validates the request
+	// body through the constraints of the model it converts to
+	requestValidation := ""
+	if withBindMiddleware {
+		sanitizeCall := ""
+		if withSanitize {
+			sanitizeCall = "\tu.Sanitize()\n"
+		}
+		requestValidation = fmt.Sprintf(`
+// Validate checks the %[1]sRequest against the %[1]s field constraints
+func (r *%[1]sRequest) Validate() error {
+	u := r.ToModel()
+%[2]s	return u.Validate()
+}
+`, structName, sanitizeCall)
+	}
+
+	// With --with-patch, PATCH bodies bind into pointer fields so an omitted
+	// field is nil rather than its zero value
+	patch := ""
+	if withPatch {
+		patch = fmt.Sprintf(`
+// %[1]sPatch represents the API request body for partially updating a %[2]s;
+// omitted fields are nil and keep their stored value
+type %[1]sPatch struct {
+%[3]s}
+
+// Apply sets the provided fields on u and returns their names, the columns
+// the repository writes
+func (p *%[1]sPatch) Apply(u *%[1]s) []string {
+	var columns []string
+%[4]s	return columns
+}
+`, structName, domainName, alignColumns(patchRows, "\t"), applyPatch.String())
+	}
+
+	content := fmt.Sprintf(`package model
+
+import (
+	%[3]s
+)
+%[4]s
+// %[2]s represents the domain model for a %[1]s
+type %[2]s struct {
+%[5]s}
+
+// %[2]sRequest represents the API request body for creating or updating a %[1]s
+type %[2]sRequest struct {
+%[6]s}
+
+// %[2]sResponse represents the API response for a %[1]s
+type %[2]sResponse struct {
+%[7]s}
+%[11]s
+// ToModel converts a %[2]sRequest to a %[2]s domain model
+func (r *%[2]sRequest) ToModel() %[2]s {
+	return %[2]s{
+%[8]s	}
+}
+
+// ToResponse converts a %[2]s domain model to a %[2]sResponse
+func (u *%[2]s) ToResponse() *%[2]sResponse {
+%[9]s}
+
+// Validate checks the %[2]s field constraints
+func (u *%[2]s) Validate() error {
+%[10]s	return nil
+}
+%[12]s%[13]s%[14]s`, domainName, structName,
+		strings.ReplaceAll(strings.Join(imports, "\n\t"), "\t\n", "\n"),
+		declarations,
+		alignColumns(modelRows, "\t"),
+		alignColumns(requestRows, "\t"),
+		alignColumns(responseRows, "\t"),
+		alignColumns(toModelRows, "\t\t"),
+		toResponse,
+		checks.String(), tableName, sanitize, requestValidation, patch)
+
+	return content
+}
+
+func repositorySource(domainName, moduleName string, opts domainOptions) string {
+	structName := capitalize(domainName)
+	varName := safeVarName(domainName)
+
+	// With --repo-errors the repository translates gorm errors into domain
+	// errors itself: ErrRecordNotFound becomes ErrNotFoundInstance and any
+	// other failure ErrInternalInstance
+	dbErr := "err"
+	getErr := "\t\treturn nil, err\n"
+	exec := func(statement string) string { return "\treturn " + statement + "\n" }
+	var errorImports string
+	if repoErrors {
+		dbErr = "errors.ErrInternalInstance.WithError(err)"
+		getErr = `		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrNotFoundInstance.WithError(err)
+		}
+		return nil, errors.ErrInternalInstance.WithError(err)
+`
+		exec = func(statement string) string {
+			return fmt.Sprintf(`	if err := %s; err != nil {
+		return errors.ErrInternalInstance.WithError(err)
+	}
+	return nil
+`, statement)
+		}
+		errorImports = fmt.Sprintf("\n\t\"%s/internal/errors\"", moduleName)
+	}
+
+	var paginationImport string
+	listParams, listResult := "", fmt.Sprintf("([]model.%s, error)", structName)
+	listBody := fmt.Sprintf(`	var %[2]ss []model.%[1]s
+	err := r.db.WithContext(ctx).Find(&%[2]ss).Error
+	if err != nil {
+		return nil, %[3]s
+	}
+	return %[2]ss, nil
+`, structName, varName, dbErr)
+	var bulkRepository string
+	if withBulk {
+		bulkRepository = fmt.Sprintf(`
+// CreateMany inserts %[2]ss in batches; gorm runs the batches in one transaction
+func (r *%[1]sRepository) CreateMany(ctx context.Context, %[2]ss []model.%[3]s) ([]model.%[3]s, error) {
+	if err := r.db.WithContext(ctx).CreateInBatches(&%[2]ss, 100).Error; err != nil {
+		return nil, %[4]s
+	}
+	return %[2]ss, nil
+}
+
+func (r *%[1]sRepository) DeleteMany(ctx context.Context, ids []uuid.UUID) error {
+%[5]s}
+`, domainName, varName, structName, dbErr,
+			exec(fmt.Sprintf("r.db.WithContext(ctx).Where(\"id IN ?\", ids).Delete(&model.%s{}).Error", structName)))
+	}
+
+	if cursorPagination {
+		paginationImport = fmt.Sprintf("\n\t\"%s/internal/pagination\"", moduleName)
+		listParams, listResult = cursorListSignature(structName)
+		listBody = fmt.Sprintf(`	query := r.db.WithContext(ctx).Order("created_at, id").Limit(limit + 1)
+	if after != nil {
+		query = query.Where("(created_at, id) > (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var %[2]ss []model.%[1]s
+	if err := query.Find(&%[2]ss).Error; err != nil {
+		return nil, nil, %[3]s
+	}
+
+	// The extra row only tells whether another page follows
+	if len(%[2]ss) <= limit {
+		return %[2]ss, nil, nil
+	}
+	%[2]ss = %[2]ss[:limit]
+	last := %[2]ss[limit-1]
+	return %[2]ss, &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+`, structName, varName, dbErr)
+	}
+
+	// With --link-pagination, List returns a page at an offset and the total
+	// count the Link header's last page is computed from
+	if linkPagination {
+		order := "created_at, id"
+		if noTimestamps {
+			order = "id"
+		}
+		listParams, listResult = linkListSignature(structName)
+		listBody = fmt.Sprintf(`	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.%[1]s{}).Count(&total).Error; err != nil {
+		return nil, 0, %[3]s
+	}
+
+	var %[2]ss []model.%[1]s
+	err := r.db.WithContext(ctx).Order("%[4]s").Offset(offset).Limit(limit).Find(&%[2]ss).Error
+	if err != nil {
+		return nil, 0, %[3]s
+	}
+	return %[2]ss, total, nil
+`, structName, varName, dbErr, order)
+	}
+
+	if withExport {
+		bulkRepository += exportRepositorySource(domainName, structName, varName, dbErr)
+	}
+
+	// With --query-builder, List compiles a <Domain>Query to the conditions,
+	// order and page of the gorm query
+	var clauseImport string
+	if opts.QueryBuilder {
+		clauseImport = "\n\t\"gorm.io/gorm/clause\""
+		listParams = fmt.Sprintf(", query *model.%sQuery", structName)
+		listBody = fmt.Sprintf(`	var %[2]ss []model.%[1]s
+	err := r.db.WithContext(ctx).Scopes(%[4]sQueryScope(query)).Find(&%[2]ss).Error
+	if err != nil {
+		return nil, %[3]s
+	}
+	return %[2]ss, nil
+`, structName, varName, dbErr, domainName)
+		bulkRepository += queryScopeSource(domainName)
+	}
+
+	if opts.ContextTransactions {
+		paginationImport += "\n\t" + transactionImport(moduleName)
+	}
+
+	stdImports := []string{`"context"`}
+	if repoErrors {
+		stdImports = append(stdImports, `stderrors "errors"`)
+	}
+
+	// With --with-patch, Patch writes only the columns the request provided;
+	// Select makes Updates write them even when they hold zero values
+	var patchMethod, patchRepository string
+	if withPatch {
+		patchMethod = fmt.Sprintf("\n\tPatch(ctx context.Context, %[1]s *model.%[2]s, columns []string) error", varName, structName)
+		patchRepository = fmt.Sprintf(`
+// Patch writes the columns of %[3]s named by columns, leaving the others unchanged
+func (r *%[1]sRepository) Patch(ctx context.Context, %[3]s *model.%[2]s, columns []string) error {
+%[4]s}
+`, domainName, structName, varName,
+			exec(fmt.Sprintf("r.db.WithContext(ctx).Model(%[1]s).Select(columns).Updates(%[1]s).Error", varName)))
+	}
+
+	content := fmt.Sprintf(`package repository
+
+import (
+	%[11]s
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"%[19]s
+%[12]s%[5]s
+	"%[1]s/pkg/%[2]s/model"
+)
+
+// %[3]sRepository defines the interface for %[2]s data operations
+type %[3]sRepository interface {
+	Create(ctx context.Context, %[4]s model.%[3]s) (*model.%[3]s, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.%[3]s, error)
+	Update(ctx context.Context, %[4]s *model.%[3]s) error%[17]s
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context%[6]s) %[7]s%[9]s
+}
+
+type %[2]sRepository struct {
+	db *gorm.DB
+}
+
+// New%[3]sRepository creates a new %[2]s repository instance
+func New%[3]sRepository(db *gorm.DB) %[3]sRepository {
+	return &%[2]sRepository{
+		db: db,
+	}
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[4]s model.%[3]s) (*model.%[3]s, error) {
+	if err := r.db.WithContext(ctx).Create(&%[4]s).Error; err != nil {
+		return nil, %[13]s
+	}
+	return &%[4]s, nil
+}
+
+func (r *%[2]sRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.%[3]s, error) {
+	var %[4]s model.%[3]s
+	err := r.db.WithContext(ctx).First(&%[4]s, "id = ?", id).Error
+	if err != nil {
+%[14]s	}
+	return &%[4]s, nil
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, %[4]s *model.%[3]s) error {
+%[15]s}
+%[18]s
+func (r *%[2]sRepository) Delete(ctx context.Context, id uuid.UUID) error {
+%[16]s}
+
+func (r *%[2]sRepository) List(ctx context.Context%[6]s) %[7]s {
+%[8]s}
+%[10]s`, moduleName, domainName, structName, varName,
+		paginationImport, listParams, listResult, listBody,
+		bulkRepositoryMethods(structName, varName)+exportRepositoryMethod(structName), bulkRepository,
+		strings.Join(stdImports, "\n\t"), errorImports, dbErr, getErr,
+		exec(fmt.Sprintf("r.db.WithContext(ctx).Save(%s).Error", varName)),
+		exec(fmt.Sprintf("r.db.WithContext(ctx).Delete(&model.%s{}, \"id = ?\", id).Error", structName)),
+		patchMethod, patchRepository, clauseImport)
+
+	if len(opts.Relations) > 0 {
+		content = preloadRelations(content, opts.Relations)
+	}
+	if opts.ContextTransactions {
+		content = contextTransactionQueries(content)
+	}
+
+	return content
+}
+
+func cachedRepositorySource(domainName, moduleName string, opts domainOptions) string {
+	structName := capitalize(domainName)
+	varName := safeVarName(domainName)
+
+	var paginationImport, listArgs, bulkCached string
+	listParams, listResult := "", fmt.Sprintf("([]model.%s, error)", structName)
+	if withBulk {
+		bulkCached = fmt.Sprintf(`
+func (r *cached%[3]sRepository) CreateMany(ctx context.Context, %[4]ss []model.%[3]s) ([]model.%[3]s, error) {
+	return r.inner.CreateMany(ctx, %[4]ss)
+}
+
+func (r *cached%[3]sRepository) DeleteMany(ctx context.Context, ids []uuid.UUID) error {
+	if err := r.inner.DeleteMany(ctx, ids); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := r.cache.Delete(ctx, %[2]sCacheKey(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`, moduleName, domainName, structName, varName)
+	}
+	if cursorPagination {
+		paginationImport = fmt.Sprintf("\n\t\"%s/internal/pagination\"", moduleName)
+		listParams, listResult = cursorListSignature(structName)
+		listArgs = ", after, limit"
+	}
+	if linkPagination {
+		listParams, listResult = linkListSignature(structName)
+		listArgs = ", offset, limit"
+	}
+	if opts.QueryBuilder {
+		listParams = fmt.Sprintf(", query *model.%sQuery", structName)
+		listArgs = ", query"
+	}
+	if withExport {
+		bulkCached += exportCachedSource(structName)
+	}
+	var patchCached string
+	if withPatch {
+		patchCached = fmt.Sprintf(`
+func (r *cached%[2]sRepository) Patch(ctx context.Context, %[3]s *model.%[2]s, columns []string) error {
+	if err := r.inner.Patch(ctx, %[3]s, columns); err != nil {
+		return err
+	}
+	return r.cache.Delete(ctx, %[1]sCacheKey(%[3]s.ID))
+}
+`, domainName, structName, varName)
+	}
+
+	content := fmt.Sprintf(`package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"time"
+
+	"github.com/google/uuid"
+%[5]s
+	"%[1]s/pkg/%[2]s/model"
+)
+
+const %[2]sCacheTTL = 5 * time.Minute
+
+// Cache is the key/value store backing cached repositories (Redis, in-memory, ...)
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type cached%[3]sRepository struct {
+	inner %[3]sRepository
+	cache Cache
+}
+
+// NewCached%[3]sRepository wraps a %[3]sRepository with a read-through cache
+// for GetByID that is invalidated on Update and Delete
+func NewCached%[3]sRepository(inner %[3]sRepository, cache Cache) %[3]sRepository {
+	return &cached%[3]sRepository{
+		inner: inner,
+		cache: cache,
+	}
+}
+
+func (r *cached%[3]sRepository) Create(ctx context.Context, %[4]s model.%[3]s) (*model.%[3]s, error) {
+	return r.inner.Create(ctx, %[4]s)
+}
+
+func (r *cached%[3]sRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.%[3]s, error) {
+	key := %[2]sCacheKey(id)
+
+	// Cache failures degrade to a miss so reads never fail because of the cache
+	if data, found, err := r.cache.Get(ctx, key); err == nil && found {
+		var %[4]s model.%[3]s
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&%[4]s); err == nil {
+			return &%[4]s, nil
+		}
+	}
+
+	%[4]s, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(%[4]s); err == nil {
+		_ = r.cache.Set(ctx, key, buf.Bytes(), %[2]sCacheTTL)
+	}
+	return %[4]s, nil
+}
+
+func (r *cached%[3]sRepository) Update(ctx context.Context, %[4]s *model.%[3]s) error {
+	if err := r.inner.Update(ctx, %[4]s); err != nil {
+		return err
+	}
+	return r.cache.Delete(ctx, %[2]sCacheKey(%[4]s.ID))
+}
+%[10]s
+func (r *cached%[3]sRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.inner.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.cache.Delete(ctx, %[2]sCacheKey(id))
+}
+
+func (r *cached%[3]sRepository) List(ctx context.Context%[6]s) %[7]s {
+	return r.inner.List(ctx%[8]s)
+}
+%[9]s
+// %[2]sCacheKey builds the cache key of a single %[2]s
+func %[2]sCacheKey(id uuid.UUID) string {
+	return "%[2]s:" + id.String()
+}
+`, moduleName, domainName, structName, varName,
+		paginationImport, listParams, listResult, listArgs, bulkCached, patchCached)
+
+	return content
+}
+
+func serviceSource(domainName, moduleName string, opts domainOptions) string {
+	structName := capitalize(domainName)
+	varName := safeVarName(domainName)
+
+	// With --otel every method runs in a span; named results let the
+	// deferred endSpan see the returned error
+	fieldRows := [][]string{{"repo", "repository." + structName + "Repository"}}
+	initRows := [][]string{{"repo:", "repo,"}}
+	params := []string{"repo repository." + structName + "Repository"}
+
+	// With --repo-errors the repository already returns domain errors, which
+	// the service passes on instead of translating them a second time
+	internalErr := "errors.ErrInternalInstance.WithError(err)"
+	getErr := `		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrNotFoundInstance.WithError(err)
+		}
+		return nil, errors.ErrInternalInstance.WithError(err)
+`
+	stderrorsImport := "\n\tstderrors \"errors\""
+	gormImport := "\n\t\"gorm.io/gorm\""
+	errorsImport := fmt.Sprintf("\t\"%s/internal/errors\"", moduleName)
+	if repoErrors || opts.MemoryRepository {
+		internalErr = "err"
+		getErr = "\t\treturn nil, err\n"
+		gormImport = ""
+		if !withBulk {
+			stderrorsImport = ""
+			if !withFeatureFlags {
+				errorsImport = ""
+			}
+		}
+	}
+
+	var otelImports, otelHelpers, paginationImport string
+	modelResult := fmt.Sprintf("(*model.%s, error)", structName)
+	modelsResult := fmt.Sprintf("([]model.%s, error)", structName)
+	listParams, listResult := "", modelsResult
+	listInterfaceResult := listResult
+	listBody := fmt.Sprintf(`	%[1]ss, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, %[2]s
+	}
+	return %[1]ss, nil
+`, varName, internalErr)
+	if cursorPagination {
+		paginationImport = fmt.Sprintf("\n\t\"%s/internal/pagination\"", moduleName)
+		listParams, listResult = cursorListSignature(structName)
+		listInterfaceResult = listResult
+		listBody = fmt.Sprintf(`	%[1]ss, next, err := s.repo.List(ctx, after, limit)
+	if err != nil {
+		return nil, nil, %[2]s
+	}
+	return %[1]ss, next, nil
+`, varName, internalErr)
+	}
+	if linkPagination {
+		listParams, listResult = linkListSignature(structName)
+		listInterfaceResult = listResult
+		listBody = fmt.Sprintf(`	%[1]ss, total, err := s.repo.List(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, %[2]s
+	}
+	return %[1]ss, total, nil
+`, varName, internalErr)
+	}
+	// With --query-builder the service validates the query before the
+	// repository compiles it
+	if opts.QueryBuilder {
+		listParams = fmt.Sprintf(", query *model.%sQuery", structName)
+		listBody = fmt.Sprintf(`	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	%[1]ss, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, %[2]s
+	}
+	return %[1]ss, nil
+`, varName, internalErr)
+	}
+	// With --list-params it takes the bound query parameters instead, and
+	// turns them into the query first
+	if withListParams {
+		listParams = fmt.Sprintf(", params model.%sListParams", structName)
+		listBody = `	query, err := params.Query()
+	if err != nil {
+		return nil, err
+	}
+
+
+` + listBody
+	}
+	listZeros := "nil, "
+	switch {
+	case cursorPagination:
+		listZeros = "nil, nil, "
+	case linkPagination:
+		listZeros = "nil, 0, "
+	}
+
+	// With --feature-flags every method first asks the injected flags whether
+	// "<domain>.<action>" is enabled
+	var flagsImport string
+	gate := func(action, zeros string) string { return "" }
+	if withFeatureFlags {
+		flagsImport = fmt.Sprintf("\n\t\"%s/internal/featureflags\"", moduleName)
+		fieldRows = append(fieldRows, []string{"flags", "featureflags.FeatureFlags"})
+		initRows = append(initRows, []string{"flags:", "flags,"})
+		params = append(params, "flags featureflags.FeatureFlags")
+		gate = func(action, zeros string) string {
+			return fmt.Sprintf(`	if !s.flags.Enabled(ctx, "%s.%s") {
+		return %serrors.ErrForbiddenInstance
+	}
+
+`, domainName, action, zeros)
+		}
+	}
+
+	errResult := "error"
+	span := func(method string) string { return "" }
+	if withOtel {
+		otelImports = `
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"`
+		fieldRows = append(fieldRows, []string{"tracer", "trace.Tracer"})
+		initRows = append(initRows, []string{"tracer:", "tracer,"})
+		params = append(params, "tracer trace.Tracer")
+		otelHelpers = `
+// endSpan records a failed call on its span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+`
+		modelResult = fmt.Sprintf("(_ *model.%s, err error)", structName)
+		modelsResult = fmt.Sprintf("(_ []model.%s, err error)", structName)
+		listResult = modelsResult
+		switch {
+		case cursorPagination:
+			listResult = fmt.Sprintf("(_ []model.%s, _ *pagination.Cursor, err error)", structName)
+		case linkPagination:
+			listResult = fmt.Sprintf("(_ []model.%s, _ int64, err error)", structName)
+		}
+		errResult = "(err error)"
+		span = func(method string) string {
+			return fmt.Sprintf(`	ctx, span := s.tracer.Start(ctx, "%sService.%s")
+	defer func() { endSpan(span, err) }()
+
+`, structName, method)
+		}
+	}
+
+	// In projects with an event bus every write publishes "<domain>.<action>"
+	// once it has been stored, the model (or its ID once deleted) as payload
+	var eventsImport, eventNames string
+	publish := func(event, payload, zeros, indent string) string { return "" }
+	if opts.DomainEvents {
+		eventsImport = fmt.Sprintf("\n\t\"%s/internal/events\"", moduleName)
+		errorsImport = fmt.Sprintf("\t\"%s/internal/errors\"", moduleName)
+		fieldRows = append(fieldRows, []string{"bus", "events.EventBus"})
+		initRows = append(initRows, []string{"bus:", "bus,"})
+		params = append(params, "bus events.EventBus")
+		eventNames = fmt.Sprintf(`
+// Events published by %[2]sService, carrying the %[1]s, or its ID once deleted
+const (
+	%[2]sCreatedEvent = "%[1]s.created"
+	%[2]sUpdatedEvent = "%[1]s.updated"
+	%[2]sDeletedEvent = "%[1]s.deleted"
+)
+`, domainName, structName)
+		publish = func(event, payload, zeros, indent string) string {
+			return fmt.Sprintf(`%[5]sif err := s.bus.Publish(ctx, events.Event{Name: %[1]s%[2]sEvent, Payload: %[3]s}); err != nil {
+%[5]s	return %[4]serrors.ErrInternalInstance.WithError(err)
+%[5]s}
+`, structName, event, payload, zeros, indent)
+		}
+	}
+
+	sanitize := func(value, indent string) string { return "" }
+	if withSanitize {
+		sanitize = func(value, indent string) string {
+			return indent + value + ".Sanitize()\n"
+		}
+	}
+
+	// With --bulk, Create<Domain>s validates every item before one batch insert
+	var bulkMethods, bulkService, strconvImport string
+	if withBulk {
+		var publishCreated, publishDeleted string
+		if opts.DomainEvents {
+			publishCreated = "\tfor i := range created {\n" + publish("Created", "&created[i]", "nil, ", "\t\t") + "\t}\n"
+			publishDeleted = "\tfor _, id := range ids {\n" + publish("Deleted", "id", "", "\t\t") + "\t}\n"
+		}
+		strconvImport = "\n\t\"strconv\""
+		bulkMethods = fmt.Sprintf(`
+	Create%[1]ss(ctx context.Context, %[2]ss []model.%[1]s) ([]model.%[1]s, error)
+	Delete%[1]ss(ctx context.Context, ids []uuid.UUID) error`, structName, varName)
+		bulkService = fmt.Sprintf(`
+func (s *%[1]sService) Create%[2]ss(ctx context.Context, %[3]ss []model.%[2]s) %[4]s {
+%[6]s	for i := range %[3]ss {
+%[8]s		if err := %[3]ss[i].Validate(); err != nil {
+			var invalid *errors.Error
+			if stderrors.As(err, &invalid) {
+				return nil, invalid.WithVariables(map[string]string{"index": strconv.Itoa(i)})
+			}
+			return nil, err
+		}
+	}
+
+	created, err := s.repo.CreateMany(ctx, %[3]ss)
+	if err != nil {
+		return nil, %[9]s
+	}
+%[10]s	return created, nil
+}
+
+func (s *%[1]sService) Delete%[2]ss(ctx context.Context, ids []uuid.UUID) %[5]s {
+%[7]s	if err := s.repo.DeleteMany(ctx, ids); err != nil {
+		return %[9]s
+	}
+%[11]s	return nil
+}
+`, domainName, structName, varName, modelsResult, errResult,
+			span("Create"+structName+"s")+gate("bulk_create", "nil, "), span("Delete"+structName+"s")+gate("bulk_delete", ""),
+			sanitize(varName+"s[i]", "\t\t"), internalErr, publishCreated, publishDeleted)
+	}
+
+	// With --with-export, Export<Domain>s hands the repository's batches to
+	// the handler streaming them
+	var exportMethod, exportService string
+	if withExport {
+		exportMethod, exportService = exportServiceSource(domainName, structName, errResult,
+			span("Export"+structName+"s")+gate("export", ""), internalErr)
+	}
+
+	// With --with-patch, Patch<Domain> applies the provided fields to the
+	// stored record and validates the result before writing those columns
+	var patchMethod, patchService string
+	if withPatch {
+		patchMethod = fmt.Sprintf("\n\tPatch%[1]s(ctx context.Context, id uuid.UUID, changes model.%[1]sPatch) (*model.%[1]s, error)", structName)
+		patchService = fmt.Sprintf(`
+func (s *%[1]sService) Patch%[2]s(ctx context.Context, id uuid.UUID, changes model.%[2]sPatch) %[4]s {
+%[5]s	%[3]s, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+%[6]s	}
+
+	columns := changes.Apply(%[3]s)
+	if len(columns) == 0 {
+		return %[3]s, nil
+	}
+%[7]s	if err := %[3]s.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Patch(ctx, %[3]s, columns); err != nil {
+		return nil, %[8]s
+	}
+%[9]s	return %[3]s, nil
+}
+`, domainName, structName, varName, modelResult,
+			span("Patch"+structName)+gate("patch", "nil, "), getErr, sanitize(varName, "\t"), internalErr,
+			publish("Updated", varName, "nil, ", "\t"))
+	}
+
+	content := fmt.Sprintf(`package service
+
+import (
+	"context"%[26]s%[24]s
+
+	"github.com/google/uuid"%[4]s%[27]s
+
+%[28]s%[36]s%[25]s%[18]s
+	"%[1]s/pkg/%[2]s/model"
+	"%[1]s/pkg/%[2]s/repository"
+)
+
+// %[3]sService defines the interface for %[2]s operations
+type %[3]sService interface {
+	Get%[3]s(ctx context.Context, id uuid.UUID) (*model.%[3]s, error)
+	Create%[3]s(ctx context.Context, %[17]s model.%[3]s) (*model.%[3]s, error)
+	Update%[3]s(ctx context.Context, %[17]s *model.%[3]s) (*model.%[3]s, error)%[31]s
+	Delete%[3]s(ctx context.Context, id uuid.UUID) error
+	List%[3]ss(ctx context.Context%[19]s) %[20]s%[22]s%[38]s
+}
+%[37]s
+type %[2]sService struct {
+%[5]s}
+
+// New%[3]sService creates a new %[2]s service instance
+func New%[3]sService(%[6]s) %[3]sService {
+	return &%[2]sService{
+%[7]s	}
+}
+
+func (s *%[2]sService) Get%[3]s(ctx context.Context, id uuid.UUID) %[9]s {
+%[12]s	%[17]s, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+%[29]s	}
+	return %[17]s, nil
+}
+
+func (s *%[2]sService) Create%[3]s(ctx context.Context, %[17]s model.%[3]s) %[9]s {
+%[13]s	if err := %[17]s.Validate(); err != nil {
+		return nil, err
+	}
+
+	created%[3]s, err := s.repo.Create(ctx, %[17]s)
+	if err != nil {
+		return nil, %[30]s
+	}
+%[33]s	return created%[3]s, nil
+}
+
+func (s *%[2]sService) Update%[3]s(ctx context.Context, %[17]s *model.%[3]s) %[9]s {
+%[14]s	if err := %[17]s.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, %[17]s); err != nil {
+		return nil, %[30]s
+	}
+%[34]s	return %[17]s, nil
+}
+%[32]s
+func (s *%[2]sService) Delete%[3]s(ctx context.Context, id uuid.UUID) %[11]s {
+%[15]s	if err := s.repo.Delete(ctx, id); err != nil {
+		return %[30]s
+	}
+%[35]s	return nil
+}
+
+func (s *%[2]sService) List%[3]ss(ctx context.Context%[19]s) %[10]s {
+%[16]s%[21]s}
+%[23]s%[39]s
+%[8]s`, moduleName, domainName, structName,
+		otelImports, alignColumns(fieldRows, "\t"), strings.Join(params, ", "), alignColumns(initRows, "\t\t"), otelHelpers,
+		modelResult, listResult, errResult,
+		span("Get"+structName)+gate("get", "nil, "), span("Create"+structName)+gate("create", "nil, ")+sanitize(varName, "\t"),
+		span("Update"+structName)+gate("update", "nil, ")+sanitize(varName, "\t"), span("Delete"+structName)+gate("delete", ""),
+		span("List"+structName+"s")+gate("list", listZeros),
+		varName, paginationImport, listParams, listInterfaceResult, listBody,
+		bulkMethods, bulkService, strconvImport, flagsImport,
+		stderrorsImport, gormImport, errorsImport, getErr, internalErr,
+		patchMethod, patchService,
+		publish("Created", "created"+structName, "nil, ", "\t"), publish("Updated", varName, "nil, ", "\t"), publish("Deleted", "id", "", "\t"),
+		eventsImport, eventNames, exportMethod, exportService)
+
+	return content
+}
+
+func handlerSource(domainName, moduleName string, fields []fieldSpec, opts domainOptions) string {
+	if opts.ChiHandlers {
+		return chiHandlerSource(domainName, moduleName)
+	}
+
+	structName := capitalize(domainName)
+	varName := safeVarName(domainName)
+
+	fieldRows := [][]string{{domainName + "Service", "service." + structName + "Service"}}
+	initRows := [][]string{{domainName + "Service:", domainName + "Service,"}}
+	params := []string{domainName + "Service service." + structName + "Service"}
+
+	// With --swag each handler's doc comment carries the swaggo annotations
+	// swag init reads; route is relative to the domain group
+	swag := func(summary, method, route string, annotations ...string) string { return "" }
+	if withSwag {
+		swag = func(summary, method, route string, annotations ...string) string {
+			var doc strings.Builder
+			fmt.Fprintf(&doc, "//\n// @Summary %s\n// @Tags %ss\n", summary, domainName)
+			forbidden := withAuthz
+			for _, annotation := range annotations {
+				// Denied --authz checks answer 403, listed in status order
+				if forbidden && annotation > "@Failure 403" && strings.HasPrefix(annotation, "@Failure") {
+					doc.WriteString("// @Failure 403 {object} errors.Error\n")
+					forbidden = false
+				}
+				fmt.Fprintf(&doc, "// %s\n", annotation)
+			}
+			fmt.Fprintf(&doc, "// @Router /%ss%s [%s]\n", domainName, route, method)
+			return doc.String()
+		}
+	}
+	idParam := fmt.Sprintf(`@Param id path string true "%s ID" format(uuid)`, structName)
+	responseType := "model." + structName + "Response"
+	failure := func(status int) string { return fmt.Sprintf("@Failure %d {object} errors.Error", status) }
+
+	// With --with-validation-middleware, httputil.Bind decodes and validates
+	// request bodies before the handler runs, and the handler only reads them
+	bindBody := func(name, bodyType string) string {
+		return fmt.Sprintf(`	var %[1]s %[2]s
+	if err := httputil.DecodeJSON(c.Request, &%[1]s); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+`, name, bodyType)
+	}
+	bind := func(bodyType string) string { return "" }
+	if withBindMiddleware {
+		bindBody = func(name, bodyType string) string {
+			return fmt.Sprintf("\t%s := httputil.Body[%s](c)\n", name, bodyType)
+		}
+		bind = func(bodyType string) string {
+			return fmt.Sprintf("httputil.Bind[%s](), ", bodyType)
+		}
+	}
+	requestType := "model." + structName + "Request"
+
+	// With --idempotent-create, Create replays the stored response of a
+	// previous request carrying the same Idempotency-Key
+	stdImports := []string{"net/http"}
+	thirdPartyImports := []string{"github.com/gin-gonic/gin"}
+	moduleImports := []string{
+		moduleName + "/internal/httputil",
+		moduleName + "/pkg/" + domainName + "/model",
+		moduleName + "/pkg/" + domainName + "/service",
+	}
+
+	// Service errors are answered with httputil.RespondError, so only the
+	// options building error values in the handler use errors
+	if withAuthz || idempotentCreate || withBulk || (opts.QueryBuilder && !withListParams) || withUserRateLimit {
+		moduleImports = append(moduleImports, moduleName+"/internal/errors")
+	}
+
+	// Projects initialized with --request-logging log each failure on the
+	// request-scoped logger before answering with it
+	if opts.ContextLoggers {
+		moduleImports = append(moduleImports, moduleName+"/internal/logging")
+	}
+
+	// With --authz every operation first asks the injected Authorizer whether
+	// the request's actor may perform "<domain>:<action>" on the resource
+	var authorizeHelper string
+	authorize := func(action, resourceID string) string { return "" }
+	if withAuthz {
+		moduleImports = append(moduleImports, moduleName+"/internal/authz")
+		fieldRows = append(fieldRows, []string{"authorizer", "authz.Authorizer"})
+		initRows = append(initRows, []string{"authorizer:", "authorizer,"})
+		params = append(params, "authorizer authz.Authorizer")
+		authorize = func(action, resourceID string) string {
+			return fmt.Sprintf(`	if !h.authorize(c, "%s:%s", %s) {
+		return
+	}
+
+`, domainName, action, resourceID)
+		}
+		authorizeHelper = fmt.Sprintf(`
+// authorize reports whether the request's actor may perform action on
+// resourceID, writing the error response when it may not
+func (h *%sHandler) authorize(c *gin.Context, action, resourceID string) bool {
+	ctx := c.Request.Context()
+	allowed, err := h.authorizer.Can(ctx, authz.ActorFromContext(ctx), action, resourceID)
+	if err != nil {
+		httputil.RespondError(c, errors.ErrInternalInstance.WithError(err))
+		return false
+	}
+	if !allowed {
+		httputil.RespondError(c, errors.ErrForbiddenInstance)
+		return false
+	}
+	return true
+}
+`, domainName)
+	}
+
+	var createPrologue string
+	createResponse := fmt.Sprintf("\thttputil.RespondJSON(c, http.StatusCreated, created%s.ToResponse())\n", structName)
+	createAnnotations := []string{
+		"@Accept json",
+		"@Produce json",
+		fmt.Sprintf(`@Param request body model.%sRequest true "%s to create"`, structName, structName),
+	}
+	if idempotentCreate {
+		createAnnotations = append(createAnnotations, `@Param Idempotency-Key header string false "Replays the stored response of a repeated key"`)
+		stdImports = append(stdImports, "encoding/json")
+		moduleImports = append(moduleImports, moduleName+"/internal/idempotency")
+		fieldRows = append(fieldRows, []string{"idempotency", "idempotency.Store"})
+		initRows = append(initRows, []string{"idempotency:", "idempotencyStore,"})
+		params = append(params, "idempotencyStore idempotency.Store")
+		createPrologue = fmt.Sprintf(`	key := c.GetHeader(idempotency.Header)
+	if key != "" {
+		record, found, err := h.idempotency.Get(c.Request.Context(), "%[1]s:"+key)
+		if err != nil {
+			httputil.RespondError(c, errors.ErrInternalInstance.WithError(err))
+			return
+		}
+		if found {
+			c.Data(record.Status, "application/json; charset=utf-8", record.Body)
+			return
+		}
+	}
+
+`, domainName)
+		createResponse = fmt.Sprintf(`
+	response := created%[2]s.ToResponse()
+	if key != "" {
+		// The %[1]s is already created, so a failed save only loses the replay
+		if body, err := json.Marshal(response); err == nil {
+			_ = h.idempotency.Save(c.Request.Context(), "%[1]s:"+key, idempotency.Record{Status: http.StatusCreated, Body: body})
+		}
+	}
+	httputil.RespondJSON(c, http.StatusCreated, response)
+`, domainName, structName)
+	}
+
+	// With --with-ratelimit-per-user the route group first counts the request
+	// against the limit of its identity in the injected Store
+	var groupMiddleware, rateLimitHelper string
+	if withUserRateLimit {
+		moduleImports = append(moduleImports, moduleName+"/internal/ratelimit")
+		fieldRows = append(fieldRows, []string{"rateLimits", "ratelimit.Store"})
+		initRows = append(initRows, []string{"rateLimits:", "rateLimitStore,"})
+		params = append(params, "rateLimitStore ratelimit.Store")
+		groupMiddleware = ", h.rateLimit"
+		rateLimitHelper = fmt.Sprintf(`
+// rateLimit rejects the requests of an identity over its rate limit with a
+// Retry-After header. Requests without an identity are limited by client IP.
+func (h *%sHandler) rateLimit(c *gin.Context) {
+	ctx := c.Request.Context()
+	key := ratelimit.IdentityFromContext(ctx)
+	if key == "" {
+		key = "ip:" + c.ClientIP()
+	}
+
+	allowed, retryAfter, err := h.rateLimits.Allow(ctx, key)
+	if err != nil {
+		c.AbortWithStatusJSON(httputil.ErrorResponse(errors.ErrInternalInstance.WithError(err)))
+		return
+	}
+	if !allowed {
+		c.Header("Retry-After", ratelimit.RetryAfter(retryAfter))
+		c.AbortWithStatusJSON(httputil.ErrorResponse(errors.ErrTooManyRequestsInstance))
+		return
+	}
+	c.Next()
+}
+`, domainName)
+	}
+
+	createAnnotations = append(createAnnotations,
+		"@Success 201 {object} "+responseType,
+		fmt.Sprintf(`@Header 201 {string} Location "URL of the created %s"`, domainName),
+		failure(400), failure(500),
+	)
+
+	listHandler := fmt.Sprintf(`// List%[3]ss handles GET /%[2]ss requests
+%[5]sfunc (h *%[2]sHandler) List%[3]ss(c *gin.Context) {
+%[6]s	%[4]ss, err := h.%[2]sService.List%[3]ss(c.Request.Context())
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	// Made rather than declared so an empty list encodes as [] instead of null
+	responses := make([]*model.%[3]sResponse, 0, len(%[4]ss))
+	for _, %[4]s := range %[4]ss {
+		responses = append(responses, %[4]s.ToResponse())
+	}
+	httputil.RespondJSON(c, http.StatusOK, responses)
+}
+`, moduleName, domainName, structName, varName,
+		swag("List "+domainName+"s", "get", "", "@Produce json", "@Success 200 {array} "+responseType, failure(500)),
+		authorize("list", `""`))
+	if cursorPagination {
+		moduleImports = append(moduleImports, moduleName+"/internal/pagination")
+		listHandler = fmt.Sprintf(`// List%[3]ss handles GET /%[2]ss?after=<cursor>&limit=<n> requests
+%[5]sfunc (h *%[2]sHandler) List%[3]ss(c *gin.Context) {
+%[6]s	after, limit, ok := httputil.ParsePageParams(c)
+	if !ok {
+		return
+	}
+
+	%[4]ss, next, err := h.%[2]sService.List%[3]ss(c.Request.Context(), after, limit)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	responses := make([]*model.%[3]sResponse, 0, len(%[4]ss))
+	for _, %[4]s := range %[4]ss {
+		responses = append(responses, %[4]s.ToResponse())
+	}
+	httputil.RespondJSON(c, http.StatusOK, pagination.NewPage(responses, next))
+}
+`, moduleName, domainName, structName, varName,
+			swag("List "+domainName+"s", "get", "",
+				"@Produce json",
+				`@Param after query string false "Cursor from the previous page's next_cursor"`,
+				`@Param limit query int false "Page size (default 20, max 100)"`,
+				fmt.Sprintf("@Success 200 {object} object{items=[]%s,%s=string}", responseType, jsonName("next_cursor")),
+				failure(400), failure(500)),
+			authorize("list", `""`))
+	}
+
+	if linkPagination {
+		listHandler = fmt.Sprintf(`// List%[3]ss handles GET /%[2]ss?offset=<n>&limit=<n> requests
+%[5]sfunc (h *%[2]sHandler) List%[3]ss(c *gin.Context) {
+%[6]s	offset, limit, ok := httputil.ParseOffsetParams(c)
+	if !ok {
+		return
+	}
+
+	%[4]ss, total, err := h.%[2]sService.List%[3]ss(c.Request.Context(), offset, limit)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	responses := make([]*model.%[3]sResponse, 0, len(%[4]ss))
+	for _, %[4]s := range %[4]ss {
+		responses = append(responses, %[4]s.ToResponse())
+	}
+	httputil.SetPageLinks(c, offset, limit, total)
+	httputil.RespondJSON(c, http.StatusOK, responses)
+}
+`, moduleName, domainName, structName, varName,
+			swag("List "+domainName+"s", "get", "",
+				"@Produce json",
+				`@Param offset query int false "Number of items to skip (default 0)"`,
+				`@Param limit query int false "Page size (default 20, max 100)"`,
+				"@Success 200 {array} "+responseType,
+				`@Header 200 {string} Link "URLs of the next, prev and last pages"`,
+				`@Header 200 {integer} X-Total-Count "Number of items in the list"`,
+				failure(400), failure(500)),
+			authorize("list", `""`))
+	}
+
+	// With --query-builder, List maps its query parameters to the calls of a
+	// <Domain>Query
+	if opts.QueryBuilder {
+		params := []string{
+			"@Produce json",
+			`@Param order_by query string false "Field to sort by, prefixed with - for descending order"`,
+			`@Param offset query int false "Number of items to skip (default 0)"`,
+			`@Param limit query int false "Page size (default all, max 100)"`,
+			"@Success 200 {array} " + responseType,
+			failure(400), failure(500),
+		}
+		for i, filter := range queryFilters(domainName, fields) {
+			params = slices.Insert(params, 1+i, fmt.Sprintf(`@Param %s query %s false "%s"`, filter.Param, swagQueryType(filter.GoType), capitalize(filter.Doc)))
+		}
+
+		// With --list-params the parameters are bound into the model's
+		// <Domain>ListParams instead of parsed by the handler
+		parse := fmt.Sprintf(`	query, ok := parse%[1]sQuery(c)
+	if !ok {
+		return
+	}
+`, structName)
+		listArg := "query"
+		var parser string
+		if withListParams {
+			parse = bindListParamsSource(structName)
+			listArg = "params"
+		} else {
+			var parserStd, parserThirdParty []string
+			parser, parserStd, parserThirdParty = queryParserSource(domainName, fields)
+			stdImports = append(stdImports, parserStd...)
+			thirdPartyImports = append(thirdPartyImports, parserThirdParty...)
+		}
+
+		listHandler = fmt.Sprintf(`// List%[3]ss handles GET /%[2]ss requests, filtered, ordered and paged by
+// their query parameters
+%[5]sfunc (h *%[2]sHandler) List%[3]ss(c *gin.Context) {
+%[6]s%[8]s
+	%[4]ss, err := h.%[2]sService.List%[3]ss(c.Request.Context(), %[9]s)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	responses := make([]*model.%[3]sResponse, 0, len(%[4]ss))
+	for _, %[4]s := range %[4]ss {
+		responses = append(responses, %[4]s.ToResponse())
+	}
+	httputil.RespondJSON(c, http.StatusOK, responses)
+}
+%[7]s`, moduleName, domainName, structName, varName,
+			swag("List "+domainName+"s", "get", "", params...),
+			authorize("list", `""`), parser, parse, listArg)
+	}
+
+	// With --bulk, /bulk endpoints create and delete many records in one call
+	var bulkRoutes, bulkMethods, bulkHandlers string
+	if withBulk {
+		var bulkDeleteAuthorization string
+		if withAuthz {
+			bulkDeleteAuthorization = fmt.Sprintf(`
+	for _, id := range ids {
+		if !h.authorize(c, "%s:delete", id.String()) {
+			return
+		}
+	}
+`, domainName)
+		}
+		stdImports = append(stdImports, "strconv")
+		thirdPartyImports = append(thirdPartyImports, "github.com/google/uuid")
+		bulkRoutes = fmt.Sprintf("\t\t%[1]sGroup.POST(\"/bulk\", %[3]sh.Create%[2]ss)\n\t\t%[1]sGroup.DELETE(\"/bulk\", %[4]sh.Delete%[2]ss)\n",
+			domainName, structName, bind("[]"+requestType), bind("[]uuid.UUID"))
+		bulkMethods = fmt.Sprintf("\n\tCreate%[1]ss(c *gin.Context)\n\tDelete%[1]ss(c *gin.Context)", structName)
+		bulkHandlers = fmt.Sprintf(`
+// maxBulkItems bounds the number of records a bulk request may carry
+const maxBulkItems = 1000
+
+// Create%[3]ss handles POST /%[2]ss/bulk requests with an array of %[2]ss
+%[5]sfunc (h *%[2]sHandler) Create%[3]ss(c *gin.Context) {
+%[7]s%[9]s	if len(requests) == 0 || len(requests) > maxBulkItems {
+		httputil.RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "request body",
+			"rule":  "min=1,max=" + strconv.Itoa(maxBulkItems),
+		}))
+		return
+	}
+
+	%[4]ss := make([]model.%[3]s, 0, len(requests))
+	for _, request := range requests {
+		%[4]ss = append(%[4]ss, request.ToModel())
+	}
+
+	created, err := h.%[2]sService.Create%[3]ss(c.Request.Context(), %[4]ss)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	responses := make([]*model.%[3]sResponse, 0, len(created))
+	for _, %[4]s := range created {
+		responses = append(responses, %[4]s.ToResponse())
+	}
+	httputil.RespondJSON(c, http.StatusCreated, responses)
+}
+
+// Delete%[3]ss handles DELETE /%[2]ss/bulk requests with an array of IDs
+%[6]sfunc (h *%[2]sHandler) Delete%[3]ss(c *gin.Context) {
+%[10]s	if len(ids) == 0 || len(ids) > maxBulkItems {
+		httputil.RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "request body",
+			"rule":  "min=1,max=" + strconv.Itoa(maxBulkItems),
+		}))
+		return
+	}
+%[8]s
+	if err := h.%[2]sService.Delete%[3]ss(c.Request.Context(), ids); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+`, moduleName, domainName, structName, varName,
+			swag("Create "+domainName+"s in bulk", "post", "/bulk",
+				"@Accept json",
+				"@Produce json",
+				fmt.Sprintf(`@Param request body []model.%sRequest true "%ss to create, at most 1000"`, structName, structName),
+				"@Success 201 {array} "+responseType,
+				failure(400), failure(500)),
+			swag("Delete "+domainName+"s in bulk", "delete", "/bulk",
+				"@Accept json",
+				`@Param ids body []string true "IDs to delete, at most 1000"`,
+				"@Success 204",
+				failure(400), failure(500)),
+			authorize("create", `""`), bulkDeleteAuthorization,
+			bindBody("requests", "[]"+requestType), bindBody("ids", "[]uuid.UUID"))
+	}
+
+	// With --with-export, GET /export streams the whole table as CSV or NDJSON
+	var exportMethod, exportRoute, exportHandler string
+	if withExport {
+		var exportImports []string
+		exportMethod = fmt.Sprintf("\n\tExport%ss(c *gin.Context)", structName)
+		exportRoute = fmt.Sprintf("\t\t%[1]sGroup.GET(\"/export\", h.Export%[2]ss)\n", domainName, structName)
+		exportHandler, exportImports = exportHandlerSource(domainName, structName, varName, fields,
+			swag("Export all "+domainName+"s", "get", "/export",
+				"@Produce text/csv",
+				"@Produce application/x-ndjson",
+				"@Success 200 {file} file \"CSV rows, or NDJSON lines of "+responseType+"\"",
+				failure(500)),
+			authorize("export", `""`))
+		stdImports = append(stdImports, exportImports...)
+	}
+
+	// With --with-patch, PATCH /:id changes only the fields the body sets
+	var patchMethod, patchRoute, patchHandler string
+	if withPatch {
+		patchType := "model." + structName + "Patch"
+		patchMethod = fmt.Sprintf("\n\tPatch%s(c *gin.Context)", structName)
+		patchRoute = fmt.Sprintf("\t\t%[1]sGroup.PATCH(\"/:id\", %[3]sh.Patch%[2]s)\n", domainName, structName, bind(patchType))
+		patchHandler = fmt.Sprintf(`
+// Patch%[2]s handles PATCH /%[1]ss/:id requests, leaving the fields the
+// body omits unchanged
+%[3]sfunc (h *%[1]sHandler) Patch%[2]s(c *gin.Context) {
+	id, ok := httputil.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+%[4]s%[5]s
+	patched%[2]s, err := h.%[1]sService.Patch%[2]s(c.Request.Context(), id, changes)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	httputil.RespondJSON(c, http.StatusOK, patched%[2]s.ToResponse())
+}
+`, domainName, structName,
+			swag("Partially update a "+domainName, "patch", "/{id}",
+				"@Accept json",
+				"@Produce json",
+				idParam,
+				fmt.Sprintf(`@Param request body %s true "Fields to change; omitted fields are left unchanged"`, patchType),
+				"@Success 200 {object} "+responseType,
+				failure(400), failure(404), failure(500)),
+			authorize("update", "id.String()"), bindBody("changes", patchType))
+	}
+
+	content := fmt.Sprintf(`package handler
+
+import (
+%[4]s)
+
+// %[3]sHandler handles HTTP requests for %[2]s operations
+type %[3]sHandler interface {
+	Get%[3]s(c *gin.Context)
+	Create%[3]s(c *gin.Context)
+	Update%[3]s(c *gin.Context)%[27]s
+	Delete%[3]s(c *gin.Context)
+	List%[3]ss(c *gin.Context)%[13]s%[32]s
+	RegisterRoutes(router gin.IRouter)
+}
+
+type %[2]sHandler struct {
+%[6]s}
+
+// New%[3]sHandler creates a new %[2]s handler instance
+func New%[3]sHandler(%[7]s) %[3]sHandler {
+	return &%[2]sHandler{
+%[8]s	}
+}
+
+// RegisterRoutes registers all %[2]s routes
+func (h *%[2]sHandler) RegisterRoutes(router gin.IRouter) {
+	%[2]sGroup := router.Group("/%[2]ss"%[30]s)
+	{
+		%[2]sGroup.GET("/:id", h.Get%[3]s)
+		%[2]sGroup.POST("", %[26]sh.Create%[3]s)
+		%[2]sGroup.PUT("/:id", %[26]sh.Update%[3]s)
+%[28]s		%[2]sGroup.DELETE("/:id", h.Delete%[3]s)
+		%[2]sGroup.GET("", h.List%[3]ss)
+%[5]s%[33]s	}
+}
+
+// Get%[3]s handles GET /%[2]ss/:id requests
+%[15]sfunc (h *%[2]sHandler) Get%[3]s(c *gin.Context) {
+	id, ok := httputil.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+%[19]s	%[11]s, err := h.%[2]sService.Get%[3]s(c.Request.Context(), id)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	httputil.RespondJSON(c, http.StatusOK, %[11]s.ToResponse())
+}
+
+// Create%[3]s handles POST /%[2]ss requests
+%[16]sfunc (h *%[2]sHandler) Create%[3]s(c *gin.Context) {
+%[20]s%[9]s%[24]s
+	created%[3]s, err := h.%[2]sService.Create%[3]s(c.Request.Context(), request.ToModel())
 	if err != nil {
-		return fmt.Errorf("failed to read module name: %w", err)
+		httputil.RespondError(c, err)
+		return
 	}
+	c.Header("Location", c.FullPath()+"/"+created%[3]s.ID.String())
+%[10]s}
 
-	// Create domain directory structure
-	domainPath := filepath.Join("pkg", domainName)
-	dirs := []string{
-		filepath.Join(domainPath, "handler"),
-		filepath.Join(domainPath, "service"),
-		filepath.Join(domainPath, "repository"),
-		filepath.Join(domainPath, "model"),
+// Update%[3]s handles PUT /%[2]ss/:id requests
+%[17]sfunc (h *%[2]sHandler) Update%[3]s(c *gin.Context) {
+	id, ok := httputil.ParseUUIDParam(c, "id")
+	if !ok {
+		return
 	}
 
-	if includeTests {
-		dirs = append(dirs,
-			filepath.Join(domainPath, "service", "test"),
-			filepath.Join(domainPath, "repository", "test"),
-		)
+%[21]s%[25]s
+	%[11]s := request.ToModel()
+	%[11]s.ID = id
+	updated%[3]s, err := h.%[2]sService.Update%[3]s(c.Request.Context(), &%[11]s)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
 	}
-
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
+	httputil.RespondJSON(c, http.StatusOK, updated%[3]s.ToResponse())
+}
+%[29]s
+// Delete%[3]s handles DELETE /%[2]ss/:id requests
+%[18]sfunc (h *%[2]sHandler) Delete%[3]s(c *gin.Context) {
+	id, ok := httputil.ParseUUIDParam(c, "id")
+	if !ok {
+		return
 	}
 
-	// Generate domain files
-	if err := generateModel(domainName, moduleName); err != nil {
-		return err
+%[22]s	err := h.%[2]sService.Delete%[3]s(c.Request.Context(), id)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
 	}
+	c.Status(http.StatusNoContent)
+}
 
-	if err := generateRepository(domainName, moduleName); err != nil {
-		return err
+%[12]s%[14]s%[34]s%[23]s%[31]s`, moduleName, domainName, structName,
+		importBlock(stdImports, thirdPartyImports, moduleImports), bulkRoutes,
+		alignColumns(fieldRows, "\t"), strings.Join(params, ", "), alignColumns(initRows, "\t\t"),
+		createPrologue, createResponse,
+		varName, listHandler, bulkMethods, bulkHandlers,
+		swag("Get a "+domainName, "get", "/{id}", "@Produce json", idParam, "@Success 200 {object} "+responseType, failure(400), failure(404), failure(500)),
+		swag("Create a "+domainName, "post", "", createAnnotations...),
+		swag("Update a "+domainName, "put", "/{id}",
+			"@Accept json",
+			"@Produce json",
+			idParam,
+			fmt.Sprintf(`@Param request body model.%sRequest true "Updated %s"`, structName, domainName),
+			"@Success 200 {object} "+responseType,
+			failure(400), failure(404), failure(500)),
+		swag("Delete a "+domainName, "delete", "/{id}", idParam, "@Success 204", failure(400), failure(404), failure(500)),
+		authorize("read", "id.String()"), authorize("create", `""`),
+		authorize("update", "id.String()"), authorize("delete", "id.String()"),
+		authorizeHelper,
+		bindBody("request", requestType), bindBody("request", requestType), bind(requestType),
+		patchMethod, patchRoute, patchHandler, groupMiddleware, rateLimitHelper,
+		exportMethod, exportRoute, exportHandler)
+
+	if opts.ContextLoggers {
+		content = errorResponsePattern.ReplaceAllString(content,
+			"${1}logging.Error(c.Request.Context(), \""+domainName+" request failed\", err)\n${0}")
 	}
 
-	if err := generateService(domainName, moduleName); err != nil {
-		return err
-	}
+	return content
+}
 
-	if err := generateHandler(domainName, moduleName); err != nil {
-		return err
+// errorResponsePattern matches a handler answering with a service error
+var errorResponsePattern = regexp.MustCompile(`(?m)^(\t+)httputil\.RespondError\(c, err\)\n`)
+
+// bulkRepositoryMethods returns the repository interface methods added by --bulk
+func bulkRepositoryMethods(structName, varName string) string {
+	if !withBulk {
+		return ""
 	}
+	return fmt.Sprintf(`
+	CreateMany(ctx context.Context, %[2]ss []model.%[1]s) ([]model.%[1]s, error)
+	DeleteMany(ctx context.Context, ids []uuid.UUID) error`, structName, varName)
+}
 
-	fmt.Printf("✅ Domain %s added successfully!\n", domainName)
-	fmt.Printf("\nGenerated files:\n")
-	fmt.Printf("  pkg/%s/model/%s.go\n", domainName, domainName)
-	fmt.Printf("  pkg/%s/repository/%s_repository.go\n", domainName, domainName)
-	fmt.Printf("  pkg/%s/service/%s_service.go\n", domainName, domainName)
-	fmt.Printf("  pkg/%s/handler/%s_handler.go\n", domainName, domainName)
+// importBlock renders the body of an import declaration, one sorted group per argument
+func importBlock(groups ...[]string) string {
+	var blocks []string
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		// Options may each ask for the same package
+		sorted := append([]string(nil), group...)
+		sort.Strings(sorted)
+		sorted = slices.Compact(sorted)
+		blocks = append(blocks, "\t\""+strings.Join(sorted, "\"\n\t\"")+"\"\n")
+	}
+	return strings.Join(blocks, "\n")
+}
 
-	return nil
+// cursorListSignature returns the parameters and results of a cursor-paginated List
+func cursorListSignature(structName string) (string, string) {
+	return ", after *pagination.Cursor, limit int", fmt.Sprintf("([]model.%s, *pagination.Cursor, error)", structName)
 }
 
-func generateModel(domainName, moduleName string) error {
-	structName := capitalize(domainName)
+func linkListSignature(structName string) (string, string) {
+	return ", offset, limit int", fmt.Sprintf("([]model.%s, int64, error)", structName)
+}
 
-	content := fmt.Sprintf(`package model
+func generatePaginationPackage(moduleName string) error {
+	content := `package pagination
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// %s represents the domain model for a %s
-type %s struct {
-	ID        uuid.UUID `+"`gorm:\"type:uuid;primary_key;default:gen_random_uuid()\" json:\"-\"`"+`
-	Name      string    `+"`gorm:\"size:255;not null\" json:\"-\"`"+`
-	CreatedAt time.Time `+"`json:\"-\"`"+`
-	UpdatedAt time.Time `+"`json:\"-\"`"+`
+// DefaultLimit and MaxLimit bound the page size of cursor-paginated lists
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Cursor is the keyset position of the last item of a page. Lists are
+// ordered by (created_at, id), so the next page starts strictly after it.
+type Cursor struct {
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+	ID        uuid.UUID ` + "`json:\"id\"`" + `
 }
 
-// %sResponse represents the API response for a %s
-type %sResponse struct {
-	ID        uuid.UUID `+"`json:\"id\"`"+`
-	Name      string    `+"`json:\"name\"`"+`
-	CreatedAt time.Time `+"`json:\"created_at\"`"+`
-	UpdatedAt time.Time `+"`json:\"updated_at\"`"+`
+// Encode returns the opaque token clients pass back as ?after=
+func Encode(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
 }
 
-// ToResponse converts a %s domain model to a %sResponse
-func (u *%s) ToResponse() *%sResponse {
-	return &%sResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+// Decode parses a token produced by Encode
+func Decode(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
 	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
 }
-`, structName, domainName, structName, structName, domainName, structName, structName, structName, structName, structName, structName)
 
-	fileName := filepath.Join("pkg", domainName, "model", domainName+".go")
-	return writeFile(fileName, content)
+// Page is the response envelope of a cursor-paginated list; NextCursor is
+// empty on the last page
+type Page[T any] struct {
+	Items      []T    ` + "`json:\"items\"`" + `
+	NextCursor string ` + "`json:\"" + jsonName("next_cursor") + ",omitempty\"`" + `
 }
 
-func generateRepository(domainName, moduleName string) error {
-	structName := capitalize(domainName)
+// NewPage builds a page from its items and the cursor of the next page
+func NewPage[T any](items []T, next *Cursor) Page[T] {
+	page := Page[T]{Items: items}
+	if next != nil {
+		page.NextCursor = Encode(*next)
+	}
+	return page
+}
+`
+	if err := writeFile(filepath.Join("internal", "pagination", "pagination.go"), content); err != nil {
+		return err
+	}
 
-	content := fmt.Sprintf(`package repository
+	// The gin side lives next to ParseUUIDParam
+	content = fmt.Sprintf(`package httputil
 
 import (
-	"context"
+	"strconv"
 
-	"github.com/google/uuid"
-	"gorm.io/gorm"
+	"github.com/gin-gonic/gin"
 
-	"%s/pkg/%s/model"
+	"%[1]s/internal/errors"
+	"%[1]s/internal/pagination"
 )
 
-// %sRepository defines the interface for %s data operations
-type %sRepository interface {
-	Create(ctx context.Context, %s model.%s) (*model.%s, error)
-	GetByID(ctx context.Context, id uuid.UUID) (*model.%s, error)
-	Update(ctx context.Context, %s *model.%s) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context) ([]model.%s, error)
+// ParsePageParams reads the ?after= cursor and the ?limit= page size. On an
+// invalid value it writes a 400 response and returns false, so the handler only returns.
+func ParsePageParams(c *gin.Context) (*pagination.Cursor, int, bool) {
+	limit := pagination.DefaultLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > pagination.MaxLimit {
+			RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+				"field": "limit",
+				"rule":  "min=1,max=" + strconv.Itoa(pagination.MaxLimit),
+			}))
+			return nil, 0, false
+		}
+		limit = n
+	}
+
+	var after *pagination.Cursor
+	if value := c.Query("after"); value != "" {
+		cursor, err := pagination.Decode(value)
+		if err != nil {
+			RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+				"field": "after",
+			}).WithError(err))
+			return nil, 0, false
+		}
+		after = cursor
+	}
+
+	return after, limit, true
 }
+`, moduleName)
 
-type %sRepository struct {
-	db *gorm.DB
+	return writeFile(filepath.Join("internal", "httputil", "pagination.go"), content)
 }
 
-// New%sRepository creates a new %s repository instance
-func New%sRepository(db *gorm.DB) %sRepository {
-	return &%sRepository{
-		db: db,
+func generateLinkPaginationPackage(moduleName string) error {
+	content := `package pagination
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize and MaxPageSize bound the page size of offset-paginated lists
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Links returns the RFC 5988 Link header of the page of limit items at
+// offset in a list of total items: rel="next", "prev" and "last" each point
+// to list with its offset and limit query parameters replaced, and are left
+// out on the pages that have no such neighbor. list is the URL of the
+// request, so links keep its path prefix and other query parameters.
+func Links(list *url.URL, offset, limit int, total int64) string {
+	link := func(offset int, rel string) string {
+		query := list.Query()
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("limit", strconv.Itoa(limit))
+		target := url.URL{Path: list.Path, RawQuery: query.Encode()}
+		return "<" + target.String() + ">; rel=\"" + rel + "\""
 	}
-}
 
-func (r *%sRepository) Create(ctx context.Context, %s model.%s) (*model.%s, error) {
-	if err := r.db.WithContext(ctx).Create(&%s).Error; err != nil {
-		return nil, err
+	var links []string
+	if int64(offset+limit) < total {
+		links = append(links, link(offset+limit, "next"))
+	}
+	if offset > 0 {
+		links = append(links, link(max(offset-limit, 0), "prev"))
+	}
+	if total > 0 {
+		last := int((total - 1) / int64(limit) * int64(limit))
+		if last != offset {
+			links = append(links, link(last, "last"))
+		}
 	}
-	return &%s, nil
+	return strings.Join(links, ", ")
 }
+`
+	if err := writeFile(filepath.Join("internal", "pagination", "links.go"), content); err != nil {
+		return err
+	}
 
-func (r *%sRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.%s, error) {
-	var %s model.%s
-	err := r.db.WithContext(ctx).First(&%s, "id = ?", id).Error
-	if err != nil {
-		return nil, err
+	content = fmt.Sprintf(`package httputil
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"%[1]s/internal/errors"
+	"%[1]s/internal/pagination"
+)
+
+// TotalCountHeader is the response header carrying the size of a paginated list
+const TotalCountHeader = "X-Total-Count"
+
+// ParseOffsetParams reads the ?offset= position and the ?limit= page size. On an
+// invalid value it writes a 400 response and returns false, so the handler only returns.
+func ParseOffsetParams(c *gin.Context) (int, int, bool) {
+	limit := pagination.DefaultPageSize
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > pagination.MaxPageSize {
+			RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+				"field": "limit",
+				"rule":  "min=1,max=" + strconv.Itoa(pagination.MaxPageSize),
+			}))
+			return 0, 0, false
+		}
+		limit = n
 	}
-	return &%s, nil
+
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+				"field": "offset",
+				"rule":  "min=0",
+			}))
+			return 0, 0, false
+		}
+		offset = n
+	}
+
+	return offset, limit, true
 }
 
-func (r *%sRepository) Update(ctx context.Context, %s *model.%s) error {
-	return r.db.WithContext(ctx).Save(%s).Error
+// SetPageLinks writes the total count and the Link header of a page
+func SetPageLinks(c *gin.Context, offset, limit int, total int64) {
+	c.Header(TotalCountHeader, strconv.FormatInt(total, 10))
+	if links := pagination.Links(c.Request.URL, offset, limit, total); links != "" {
+		c.Header("Link", links)
+	}
 }
+`, moduleName)
 
-func (r *%sRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&model.%s{}, "id = ?", id).Error
+	return writeFile(filepath.Join("internal", "httputil", "links.go"), content)
 }
 
-func (r *%sRepository) List(ctx context.Context) ([]model.%s, error) {
-	var %ss []model.%s
-	err := r.db.WithContext(ctx).Find(&%ss).Error
+func generateHTTPUtilPackage(moduleName string) error {
+	content := fmt.Sprintf(`package httputil
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"%[1]s/internal/errors"
+)
+
+// ParseUUIDParam parses a UUID path parameter. On failure it writes a 400
+// response naming the parameter and returns false, so the handler only returns.
+func ParseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
 	if err != nil {
-		return nil, err
+		RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": name,
+		}).WithError(err))
+		return uuid.Nil, false
 	}
-	return %ss, nil
+	return id, true
 }
-`, moduleName, domainName, structName, domainName, structName, domainName, structName, structName, structName, domainName, structName, structName, domainName, structName, structName, domainName, structName, structName, domainName, structName, domainName, structName, structName, domainName, structName, structName, structName, domainName, structName, structName, domainName, structName, domainName, structName, domainName, structName, structName, structName, domainName, structName, domainName, domainName, structName, domainName, domainName)
+`, moduleName)
 
-	fileName := filepath.Join("pkg", domainName, "repository", domainName+"_repository.go")
-	return writeFile(fileName, content)
+	return writeFile(filepath.Join("internal", "httputil", "params.go"), content)
 }
 
-func generateService(domainName, moduleName string) error {
-	structName := capitalize(domainName)
+// generateRespondHelper writes internal/httputil/respond.go, through which
+// generated handlers write every JSON response
+func generateRespondHelper(moduleName string) error {
+	return writeFile(filepath.Join("internal", "httputil", "respond.go"), respondHelperSource(moduleName))
+}
 
-	content := fmt.Sprintf(`package service
+// respondHelperSource renders internal/httputil/respond.go
+func respondHelperSource(moduleName string) string {
+	return fmt.Sprintf(`package httputil
 
 import (
-	"context"
-
-	"github.com/google/uuid"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
 
-	"%s/internal/errors"
-	"%s/pkg/%s/model"
-	"%s/pkg/%s/repository"
+	"%[1]s/internal/errors"
 )
 
-// %sService defines the interface for %s operations
-type %sService interface {
-	Get%s(ctx context.Context, id uuid.UUID) (*model.%s, error)
-	Create%s(ctx context.Context, %s model.%s) (*model.%s, error)
-	Update%s(ctx context.Context, %s *model.%s) (*model.%s, error)
-	Delete%s(ctx context.Context, id uuid.UUID) error
-	List%ss(ctx context.Context) ([]model.%s, error)
+// Responder is the part of a web framework's request context the helpers
+// write through. *gin.Context implements it; HTTPResponder adapts a net/http
+// ResponseWriter, and other frameworks need an adapter of the same size.
+type Responder interface {
+	JSON(status int, body any)
 }
 
-type %sService struct {
-	repo repository.%sRepository
+// RespondJSON writes body as the JSON response with status
+func RespondJSON(r Responder, status int, body any) {
+	r.JSON(status, body)
 }
 
-// New%sService creates a new %s service instance
-func New%sService(repo repository.%sRepository) %sService {
-	return &%sService{
-		repo: repo,
-	}
+// ErrorBody is the JSON body of every error response. Its keys are the
+// field names of errors.Error, so clients can decode it into one.
+type ErrorBody struct {
+	Code      string
+	Message   string
+	Variables map[string]string `+"`json:\",omitempty\"`"+`
 }
 
-func (s *%sService) Get%s(ctx context.Context, id uuid.UUID) (*model.%s, error) {
-	%s, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, errors.ErrInternalInstance.WithError(err)
+// internalErrorMessage is the whole message of a 5xx response
+const internalErrorMessage = "Internal server error"
+
+// ErrorResponse returns the status and body answering err: a domain error
+// with its status, anything else as a 500. The body never carries the
+// wrapped error, and 5xx bodies are the same fixed one, so database details
+// stay in the logs. Change the envelope here to change it for every handler.
+func ErrorResponse(err error) (int, ErrorBody) {
+	var domainErr *errors.Error
+	if !stderrors.As(err, &domainErr) {
+		return http.StatusInternalServerError, ErrorBody{Code: errors.ErrInternal, Message: internalErrorMessage}
 	}
-	return %s, nil
-}
 
-func (s *%sService) Create%s(ctx context.Context, %s model.%s) (*model.%s, error) {
-	created%s, err := s.repo.Create(ctx, %s)
-	if err != nil {
-		return nil, errors.ErrInternalInstance.WithError(err)
+	status := domainErr.HTTPStatus()
+	if status >= http.StatusInternalServerError {
+		return status, ErrorBody{Code: errors.ErrInternal, Message: internalErrorMessage}
+	}
+	return status, ErrorBody{
+		Code:      domainErr.Code,
+		Message:   domainErr.Message,
+		Variables: domainErr.Variables,
 	}
-	return created%s, nil
 }
 
-func (s *%sService) Update%s(ctx context.Context, %s *model.%s) (*model.%s, error) {
-	if err := s.repo.Update(ctx, %s); err != nil {
-		return nil, errors.ErrInternalInstance.WithError(err)
-	}
-	return %s, nil
+// RespondError writes err as the error response built by ErrorResponse, so
+// every error body has the same shape
+func RespondError(r Responder, err error) {
+	r.JSON(ErrorResponse(err))
 }
 
-func (s *%sService) Delete%s(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return errors.ErrInternalInstance.WithError(err)
+// HTTPResponder adapts w to Responder, for handlers on net/http routers
+func HTTPResponder(w http.ResponseWriter) Responder {
+	return httpResponder{w}
+}
+
+type httpResponder struct {
+	w http.ResponseWriter
+}
+
+func (r httpResponder) JSON(status int, body any) {
+	r.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	r.w.WriteHeader(status)
+	_ = json.NewEncoder(r.w).Encode(body)
+}
+`, moduleName)
+}
+
+// generateDecodeHelper writes internal/httputil/decode.go, through which
+// generated handlers decode JSON request bodies
+func generateDecodeHelper(moduleName string) error {
+	content := fmt.Sprintf(`package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"%[1]s/internal/errors"
+)
+
+// DecodeJSON decodes the JSON body of r into dst. Unlike gin's
+// ShouldBindJSON it rejects a missing or null body instead of leaving dst
+// zero, and its ErrInvalidInstance tells the failures apart by rule:
+// "required" for a missing body, "json" for a malformed one and "type" for
+// a value of the wrong type, with field naming it. A body that decodes but
+// lacks required fields is left to the model's Validate, which names them.
+func DecodeJSON(r *http.Request, dst any) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return invalidBody("request body", "required", nil)
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		if stderrors.Is(err, io.EOF) {
+			return invalidBody("request body", "required", nil)
+		}
+		return invalidBody("request body", "json", err)
+	}
+	// A second value after the first makes the body malformed too
+	if err := decoder.Decode(&struct{}{}); !stderrors.Is(err, io.EOF) {
+		return invalidBody("request body", "json", err)
+	}
+	if bytes.Equal(raw, []byte("null")) {
+		return invalidBody("request body", "required", nil)
+	}
+
+	if err := json.Unmarshal(raw, dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if stderrors.As(err, &typeErr) && typeErr.Field != "" {
+			return invalidBody(typeErr.Field, "type", err)
+		}
+		return invalidBody("request body", "type", err)
 	}
 	return nil
 }
 
-func (s *%sService) List%ss(ctx context.Context) ([]model.%s, error) {
-	%ss, err := s.repo.List(ctx)
+func invalidBody(field, rule string, err error) *errors.Error {
+	invalid := errors.ErrInvalidInstance.WithVariables(map[string]string{
+		"field": field,
+		"rule":  rule,
+	})
 	if err != nil {
-		return nil, errors.ErrInternalInstance.WithError(err)
+		return invalid.WithError(err)
 	}
-	return %ss, nil
+	return invalid
 }
-`, moduleName, domainName, moduleName, domainName, structName, domainName, structName, structName, structName, structName, domainName, structName, structName, structName, domainName, structName, structName, structName, structName, domainName, structName, structName, structName, structName, domainName, structName, structName, domainName, structName, domainName, structName, structName, structName, domainName, domainName, domainName, structName, structName, domainName, structName, structName, domainName, structName, structName, structName, domainName, structName, structName, domainName, structName, structName, structName, structName, domainName, domainName, domainName)
+`, moduleName)
 
-	fileName := filepath.Join("pkg", domainName, "service", domainName+"_service.go")
-	return writeFile(fileName, content)
+	return writeFile(filepath.Join("internal", "httputil", "decode.go"), content)
 }
 
-func generateHandler(domainName, moduleName string) error {
-	structName := capitalize(domainName)
+// generateBindHelper writes internal/httputil/bind.go: a gin middleware
+// decoding and validating a JSON request body before the handler runs
+func generateBindHelper() error {
+	content := `package httputil
 
-	content := fmt.Sprintf(`package handler
+import "github.com/gin-gonic/gin"
 
-import (
-	"net/http"
+// Validatable is a request body checking its own constraints, e.g. a
+// generated Request whose Validate applies the model's field rules
+type Validatable interface {
+	Validate() error
+}
 
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+// bodyKey stores the bound body in the gin context
+const bodyKey = "httputil.body"
+
+// Bind returns middleware decoding the JSON request body into a T and
+// running its Validate method. Invalid bodies are answered with 400 before
+// the handler runs; valid ones are read with Body.
+func Bind[T any]() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body T
+		if err := DecodeJSON(c.Request, &body); err != nil {
+			c.AbortWithStatusJSON(ErrorResponse(err))
+			return
+		}
+		if v, ok := any(&body).(Validatable); ok {
+			if err := v.Validate(); err != nil {
+				c.AbortWithStatusJSON(ErrorResponse(err))
+				return
+			}
+		}
 
-	"%s/internal/errors"
-	"%s/pkg/%s/model"
-	"%s/pkg/%s/service"
-)
+		c.Set(bodyKey, body)
+		c.Next()
+	}
+}
 
-// %sHandler handles HTTP requests for %s operations
-type %sHandler interface {
-	Get%s(c *gin.Context)
-	Create%s(c *gin.Context)
-	Update%s(c *gin.Context)
-	Delete%s(c *gin.Context)
-	List%ss(c *gin.Context)
-	RegisterRoutes(router gin.IRouter)
+// Body returns the request body Bind decoded and validated. It panics on
+// routes registered without Bind[T].
+func Body[T any](c *gin.Context) T {
+	return c.MustGet(bodyKey).(T)
 }
+`
 
-type %sHandler struct {
-	%sService service.%sService
+	return writeFile(filepath.Join("internal", "httputil", "bind.go"), content)
 }
 
-// New%sHandler creates a new %s handler instance
-func New%sHandler(%sService service.%sService) %sHandler {
-	return &%sHandler{
-		%sService: %sService,
-	}
+func generateIdempotencyPackage() error {
+	content := `package idempotency
+
+import "context"
+
+// Header is the request header carrying the client's idempotency key
+const Header = "Idempotency-Key"
+
+// Record is a stored response replayed for a repeated idempotency key
+type Record struct {
+	Status int
+	Body   []byte
 }
 
-// RegisterRoutes registers all %s routes
-func (h *%sHandler) RegisterRoutes(router gin.IRouter) {
-	%sGroup := router.Group("/%ss")
-	{
-		%sGroup.GET("/:id", h.Get%s)
-		%sGroup.POST("", h.Create%s)
-		%sGroup.PUT("/:id", h.Update%s)
-		%sGroup.DELETE("/:id", h.Delete%s)
-		%sGroup.GET("", h.List%ss)
-	}
+// Store keeps responses by idempotency key (Redis, a database table, ...).
+// Implementations should expire records after the retry window clients use.
+type Store interface {
+	Get(ctx context.Context, key string) (Record, bool, error)
+	Save(ctx context.Context, key string, record Record) error
 }
+`
 
-// Get%s handles GET /%ss/:id requests
-func (h *%sHandler) Get%s(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.ErrInvalidInstance.WithVariables(map[string]string{
-			"field": "id",
-		}).WithError(err))
-		return
-	}
+	return writeFile(filepath.Join("internal", "idempotency", "idempotency.go"), content)
+}
 
-	%s, err := h.%sService.Get%s(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		return
-	}
-	c.JSON(http.StatusOK, %s.ToResponse())
+func generateFeatureFlagsPackage() error {
+	content := `package featureflags
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FeatureFlags decides whether a feature ("user.create") is enabled for a
+// request. Swap the env implementation for a flag service behind this interface.
+type FeatureFlags interface {
+	Enabled(ctx context.Context, name string) bool
 }
 
-// Create%s handles POST /%ss requests
-func (h *%sHandler) Create%s(c *gin.Context) {
-	var %s model.%s
-	if err := c.ShouldBindJSON(&%s); err != nil {
-		c.JSON(http.StatusBadRequest, errors.ErrInvalidInstance.WithVariables(map[string]string{
-			"field": "request body",
-		}).WithError(err))
-		return
-	}
+type envFeatureFlags struct{}
 
-	created%s, err := h.%sService.Create%s(c.Request.Context(), %s)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		return
-	}
-	c.JSON(http.StatusCreated, created%s.ToResponse())
+// NewEnvFeatureFlags reads each flag from an environment variable named
+// after it, FEATURE_USER_CREATE for "user.create". Unset or malformed
+// variables leave the feature enabled.
+func NewEnvFeatureFlags() FeatureFlags {
+	return &envFeatureFlags{}
 }
 
-// Update%s handles PUT /%ss/:id requests
-func (h *%sHandler) Update%s(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
+func (f *envFeatureFlags) Enabled(ctx context.Context, name string) bool {
+	enabled, err := strconv.ParseBool(os.Getenv(EnvKey(name)))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.ErrInvalidInstance.WithVariables(map[string]string{
-			"field": "id",
-		}).WithError(err))
-		return
+		return true
 	}
+	return enabled
+}
 
-	var %s model.%s
-	if err := c.ShouldBindJSON(&%s); err != nil {
-		c.JSON(http.StatusBadRequest, errors.ErrInvalidInstance.WithVariables(map[string]string{
-			"field": "request body",
-		}).WithError(err))
-		return
-	}
+// EnvKey returns the environment variable holding a flag
+func EnvKey(name string) string {
+	return "FEATURE_" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+`
 
-	%s.ID = id
-	updated%s, err := h.%sService.Update%s(c.Request.Context(), &%s)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		return
-	}
-	c.JSON(http.StatusOK, updated%s.ToResponse())
+	return writeFile(filepath.Join("internal", "featureflags", "featureflags.go"), content)
 }
 
-// Delete%s handles DELETE /%ss/:id requests
-func (h *%sHandler) Delete%s(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.ErrInvalidInstance.WithVariables(map[string]string{
-			"field": "id",
-		}).WithError(err))
-		return
-	}
+func generateAuthzPackage() error {
+	content := `package authz
 
-	err = h.%sService.Delete%s(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		return
+import "context"
+
+// Authorizer decides whether an actor may perform an action ("user:read")
+// on a resource. resourceID is empty for collection actions such as
+// "user:list" and "user:create". Put RBAC or ABAC policies behind it.
+type Authorizer interface {
+	Can(ctx context.Context, actor, action, resourceID string) (bool, error)
+}
+
+type allowAll struct{}
+
+// NewAllowAll returns the default Authorizer, which permits everything.
+// Replace it with a real policy before exposing the API.
+func NewAllowAll() Authorizer {
+	return &allowAll{}
+}
+
+func (a *allowAll) Can(ctx context.Context, actor, action, resourceID string) (bool, error) {
+	return true, nil
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the authenticated actor; call it
+// from the authentication middleware
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or "" for
+// anonymous requests
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+`
+
+	return writeFile(filepath.Join("internal", "authz", "authz.go"), content)
+}
+
+func generateRateLimitPackage() error {
+	content := `package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Store counts the requests of each key (user ID, API key, client IP) and
+// decides whether they are within its limit. Put Redis or another shared
+// backend behind it when several instances serve the API.
+type Store interface {
+	// Allow counts a request of key and reports whether it is within the
+	// limit; when it is not, retryAfter is the time until it will be
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+type inMemoryStore struct {
+	mu      sync.Mutex
+	limit   int
+	period  time.Duration
+	windows map[string]*window
+}
+
+// NewInMemoryStore returns a Store allowing limit requests per key in each
+// fixed window of period, kept in the memory of this instance
+func NewInMemoryStore(limit int, period time.Duration) Store {
+	return &inMemoryStore{
+		limit:   limit,
+		period:  period,
+		windows: make(map[string]*window),
 	}
-	c.Status(http.StatusNoContent)
 }
 
-// List%ss handles GET /%ss requests
-func (h *%sHandler) List%ss(c *gin.Context) {
-	%ss, err := h.%sService.List%ss(c.Request.Context())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		return
+func (s *inMemoryStore) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	w, ok := s.windows[key]
+	if !ok || now.Sub(w.start) >= s.period {
+		// Drop the windows that are over, so idle keys do not accumulate
+		for k, other := range s.windows {
+			if now.Sub(other.start) >= s.period {
+				delete(s.windows, k)
+			}
+		}
+		w = &window{start: now}
+		s.windows[key] = w
 	}
 
-	var responses []*model.%sResponse
-	for _, %s := range %ss {
-		responses = append(responses, %s.ToResponse())
+	if w.count >= s.limit {
+		return false, w.start.Add(s.period).Sub(now), nil
 	}
-	
-	c.JSON(http.StatusOK, responses)
+	w.count++
+	return true, 0, nil
+}
+
+// RetryAfter formats a wait as the Retry-After header's whole seconds,
+// rounded up
+func RetryAfter(wait time.Duration) string {
+	return strconv.Itoa(int((wait + time.Second - 1) / time.Second))
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the identity requests are
+// limited by, such as the user ID or API key; call it from the
+// authentication middleware
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
 }
-`, moduleName, domainName, moduleName, domainName, structName, domainName, structName, structName, structName, structName, structName, structName, domainName, structName, domainName, structName, structName, domainName, structName, domainName, structName, structName, domainName, structName, domainName, domainName, domainName, domainName, domainName, structName, domainName, structName, domainName, structName, domainName, structName, structName, domainName, domainName, structName, structName, domainName, domainName, structName, structName, domainName, structName, structName, domainName, structName, domainName, structName, domainName, structName, structName, structName, structName, structName, domainName, structName, structName, domainName, structName, structName, structName, domainName, structName, structName, domainName, structName, structName, structName, domainName, structName, domainName, structName, structName, structName, structName, domainName, structName, domainName, domainName, structName, structName, domainName, domainName, domainName, structName, domainName, domainName, structName, domainName, domainName)
 
-	fileName := filepath.Join("pkg", domainName, "handler", domainName+"_handler.go")
-	return writeFile(fileName, content)
+// IdentityFromContext returns the identity stored by WithIdentity, or "" for
+// anonymous requests
+func IdentityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(identityKey{}).(string)
+	return identity
+}
+`
+
+	return writeFile(filepath.Join("internal", "ratelimit", "ratelimit.go"), content)
 }
 
 func getModuleName() (string, error) {
@@ -450,6 +2930,38 @@ func getModuleName() (string, error) {
 	return "", fmt.Errorf("could not parse module name from go.mod")
 }
 
+// reservedIdents are identifiers generated code uses for imports and locals,
+// which a domain-named variable must not shadow
+var reservedIdents = map[string]bool{
+	"authz": true, "bytes": true, "codes": true, "context": true,
+	"errors": true, "featureflags": true, "gin": true, "gob": true,
+	"gorm": true, "handler": true, "http": true, "httputil": true,
+	"idempotency": true, "json": true, "logging": true, "model": true,
+	"pagination": true, "ratelimit": true, "repository": true, "service": true,
+	"stderrors": true, "strconv": true, "time": true, "trace": true,
+	"transaction": true, "uuid": true,
+	"after": true, "allowed": true, "body": true, "buf": true, "c": true,
+	"cache": true, "count": true, "created": true, "ctx": true, "data": true,
+	"db": true, "err": true, "found": true, "h": true, "i": true, "id": true,
+	"ids": true, "inner": true, "invalid": true, "key": true, "last": true,
+	"limit": true, "next": true, "ok": true, "query": true, "r": true,
+	"record": true, "repo": true, "request": true, "requests": true,
+	"response": true, "responses": true, "retryAfter": true, "router": true, "s": true,
+	"span": true, "tracer": true,
+}
+
+// safeVarName returns the local variable name used for a domain entity,
+// suffixing "Item" when the domain name (or its plural) is a keyword,
+// a predeclared identifier or an identifier the generated code relies on
+func safeVarName(domainName string) string {
+	for _, name := range []string{domainName, domainName + "s"} {
+		if token.IsKeyword(name) || types.Universe.Lookup(name) != nil || reservedIdents[name] {
+			return domainName + "Item"
+		}
+	}
+	return domainName
+}
+
 func capitalize(s string) string {
 	if len(s) == 0 {
 		return s
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// chiHandlerSource renders the handler of a domain in a chi project:
+// net/http handler methods registered on a chi.Router, decoding request
+// bodies with httputil.DecodeJSON and answering through httputil.HTTPResponder
+func chiHandlerSource(domainName, moduleName string) string {
+	structName := capitalize(domainName)
+	varName := safeVarName(domainName)
+
+	stdImports := []string{"net/http", "path"}
+	thirdPartyImports := []string{"github.com/go-chi/chi/v5"}
+	moduleImports := []string{
+		moduleName + "/internal/httputil",
+		moduleName + "/pkg/" + domainName + "/model",
+		moduleName + "/pkg/" + domainName + "/service",
+	}
+
+	decodeBody := func(name, bodyType string) string {
+		return fmt.Sprintf(`	var %[1]s %[2]s
+	if err := httputil.DecodeJSON(r, &%[1]s); err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+`, name, bodyType)
+	}
+
+	// With --with-patch, PATCH /{id} changes only the fields the body sets
+	var patchMethod, patchRoute, patchHandler string
+	if withPatch {
+		patchMethod = fmt.Sprintf("\n\tPatch%s(w http.ResponseWriter, r *http.Request)", structName)
+		patchRoute = fmt.Sprintf("\t\tr.Patch(\"/{id}\", h.Patch%s)\n", structName)
+		patchHandler = fmt.Sprintf(`
+// Patch%[2]s handles PATCH /%[1]ss/{id} requests, leaving the fields the
+// body omits unchanged
+func (h *%[1]sHandler) Patch%[2]s(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+	id, ok := httputil.ParseUUIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+%[3]s
+	patched%[2]s, err := h.%[1]sService.Patch%[2]s(r.Context(), id, changes)
+	if err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+	httputil.RespondJSON(rw, http.StatusOK, patched%[2]s.ToResponse())
+}
+`, domainName, structName, decodeBody("changes", "model."+structName+"Patch"))
+	}
+
+	// With --bulk, /bulk endpoints create and delete many records in one call
+	var bulkMethods, bulkRoutes, bulkHandlers string
+	if withBulk {
+		stdImports = append(stdImports, "strconv")
+		moduleImports = append(moduleImports, moduleName+"/internal/errors")
+		thirdPartyImports = append(thirdPartyImports, "github.com/google/uuid")
+		bulkMethods = fmt.Sprintf(`
+	Create%[1]ss(w http.ResponseWriter, r *http.Request)
+	Delete%[1]ss(w http.ResponseWriter, r *http.Request)`, structName)
+		bulkRoutes = fmt.Sprintf("\t\tr.Post(\"/bulk\", h.Create%[1]ss)\n\t\tr.Delete(\"/bulk\", h.Delete%[1]ss)\n", structName)
+		bulkHandlers = fmt.Sprintf(`
+// maxBulkItems bounds the number of records a bulk request may carry
+const maxBulkItems = 1000
+
+// Create%[2]ss handles POST /%[1]ss/bulk requests with an array of %[1]ss
+func (h *%[1]sHandler) Create%[2]ss(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+%[4]s	if len(requests) == 0 || len(requests) > maxBulkItems {
+		httputil.RespondError(rw, errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "request body",
+			"rule":  "min=1,max=" + strconv.Itoa(maxBulkItems),
+		}))
+		return
+	}
+
+	%[3]ss := make([]model.%[2]s, 0, len(requests))
+	for _, request := range requests {
+		%[3]ss = append(%[3]ss, request.ToModel())
+	}
+
+	created, err := h.%[1]sService.Create%[2]ss(r.Context(), %[3]ss)
+	if err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+
+	responses := make([]*model.%[2]sResponse, 0, len(created))
+	for _, %[3]s := range created {
+		responses = append(responses, %[3]s.ToResponse())
+	}
+	httputil.RespondJSON(rw, http.StatusCreated, responses)
+}
+
+// Delete%[2]ss handles DELETE /%[1]ss/bulk requests with an array of IDs
+func (h *%[1]sHandler) Delete%[2]ss(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+%[5]s	if len(ids) == 0 || len(ids) > maxBulkItems {
+		httputil.RespondError(rw, errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "request body",
+			"rule":  "min=1,max=" + strconv.Itoa(maxBulkItems),
+		}))
+		return
+	}
+
+	if err := h.%[1]sService.Delete%[2]ss(r.Context(), ids); err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+`, domainName, structName, varName,
+			decodeBody("requests", "[]model."+structName+"Request"), decodeBody("ids", "[]uuid.UUID"))
+	}
+
+	return fmt.Sprintf(`package handler
+
+import (
+%[4]s)
+
+// %[3]sHandler handles HTTP requests for %[2]s operations
+type %[3]sHandler interface {
+	Get%[3]s(w http.ResponseWriter, r *http.Request)
+	Create%[3]s(w http.ResponseWriter, r *http.Request)
+	Update%[3]s(w http.ResponseWriter, r *http.Request)%[7]s
+	Delete%[3]s(w http.ResponseWriter, r *http.Request)
+	List%[3]ss(w http.ResponseWriter, r *http.Request)%[10]s
+	RegisterRoutes(router chi.Router)
+}
+
+type %[2]sHandler struct {
+	%[2]sService service.%[3]sService
+}
+
+// New%[3]sHandler creates a new %[2]s handler instance
+func New%[3]sHandler(%[2]sService service.%[3]sService) %[3]sHandler {
+	return &%[2]sHandler{
+		%[2]sService: %[2]sService,
+	}
+}
+
+// RegisterRoutes registers all %[2]s routes
+func (h *%[2]sHandler) RegisterRoutes(router chi.Router) {
+	router.Route("/%[2]ss", func(r chi.Router) {
+		r.Get("/{id}", h.Get%[3]s)
+		r.Post("/", h.Create%[3]s)
+		r.Put("/{id}", h.Update%[3]s)
+%[8]s		r.Delete("/{id}", h.Delete%[3]s)
+		r.Get("/", h.List%[3]ss)
+%[11]s	})
+}
+
+// Get%[3]s handles GET /%[2]ss/{id} requests
+func (h *%[2]sHandler) Get%[3]s(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+	id, ok := httputil.ParseUUIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	%[5]s, err := h.%[2]sService.Get%[3]s(r.Context(), id)
+	if err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+	httputil.RespondJSON(rw, http.StatusOK, %[5]s.ToResponse())
+}
+
+// Create%[3]s handles POST /%[2]ss requests
+func (h *%[2]sHandler) Create%[3]s(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+%[6]s
+	created%[3]s, err := h.%[2]sService.Create%[3]s(r.Context(), request.ToModel())
+	if err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+	w.Header().Set("Location", path.Join(r.URL.Path, created%[3]s.ID.String()))
+	httputil.RespondJSON(rw, http.StatusCreated, created%[3]s.ToResponse())
+}
+
+// Update%[3]s handles PUT /%[2]ss/{id} requests
+func (h *%[2]sHandler) Update%[3]s(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+	id, ok := httputil.ParseUUIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+%[6]s
+	%[5]s := request.ToModel()
+	%[5]s.ID = id
+	updated%[3]s, err := h.%[2]sService.Update%[3]s(r.Context(), &%[5]s)
+	if err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+	httputil.RespondJSON(rw, http.StatusOK, updated%[3]s.ToResponse())
+}
+%[9]s
+// Delete%[3]s handles DELETE /%[2]ss/{id} requests
+func (h *%[2]sHandler) Delete%[3]s(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+	id, ok := httputil.ParseUUIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.%[2]sService.Delete%[3]s(r.Context(), id); err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List%[3]ss handles GET /%[2]ss requests
+func (h *%[2]sHandler) List%[3]ss(w http.ResponseWriter, r *http.Request) {
+	rw := httputil.HTTPResponder(w)
+	%[5]ss, err := h.%[2]sService.List%[3]ss(r.Context())
+	if err != nil {
+		httputil.RespondError(rw, err)
+		return
+	}
+
+	// Made rather than declared so an empty list encodes as [] instead of null
+	responses := make([]*model.%[3]sResponse, 0, len(%[5]ss))
+	for _, %[5]s := range %[5]ss {
+		responses = append(responses, %[5]s.ToResponse())
+	}
+	httputil.RespondJSON(rw, http.StatusOK, responses)
+}
+%[12]s`, moduleName, domainName, structName,
+		importBlock(stdImports, thirdPartyImports, moduleImports),
+		varName, decodeBody("request", "model."+structName+"Request"),
+		patchMethod, patchRoute, patchHandler, bulkMethods, bulkRoutes, bulkHandlers)
+}
+
+// generateChiHTTPUtilPackage writes the internal/httputil helpers of chi
+// projects, reading path parameters with chi.URLParam
+func generateChiHTTPUtilPackage(moduleName string) error {
+	content := fmt.Sprintf(`package httputil
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"%[1]s/internal/errors"
+)
+
+// ParseUUIDParam parses a UUID path parameter. On failure it writes a 400
+// response naming the parameter and returns false, so the handler only returns.
+func ParseUUIDParam(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, name))
+	if err != nil {
+		RespondError(HTTPResponder(w), errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": name,
+		}).WithError(err))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+`, moduleName)
+
+	return writeFile(filepath.Join("internal", "httputil", "params.go"), content)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// exportBatchSize is the number of rows --with-export reads per query
+const exportBatchSize = 500
+
+// exportRepositoryMethod returns the repository interface method added by --with-export
+func exportRepositoryMethod(structName string) string {
+	if !withExport {
+		return ""
+	}
+	return fmt.Sprintf("\n\tExport(ctx context.Context, batchSize int, fn func([]model.%s) error) error", structName)
+}
+
+// exportRepositorySource renders the repository's Export, reading the table
+// in primary key order one batch at a time
+func exportRepositorySource(domainName, structName, varName, dbErr string) string {
+	return fmt.Sprintf(`
+// Export calls fn with every %[1]s, batchSize at a time in ID order, so
+// callers can stream the table without loading it whole. An error from fn
+// stops the export and is returned.
+func (r *%[1]sRepository) Export(ctx context.Context, batchSize int, fn func([]model.%[2]s) error) error {
+	var %[3]ss []model.%[2]s
+	err := r.db.WithContext(ctx).FindInBatches(&%[3]ss, batchSize, func(*gorm.DB, int) error {
+		return fn(%[3]ss)
+	}).Error
+	if err != nil {
+		return %[4]s
+	}
+	return nil
+}
+`, domainName, structName, varName, dbErr)
+}
+
+// exportCachedSource renders the cached repository's Export, which bypasses
+// the cache like List
+func exportCachedSource(structName string) string {
+	return fmt.Sprintf(`
+func (r *cached%[1]sRepository) Export(ctx context.Context, batchSize int, fn func([]model.%[1]s) error) error {
+	return r.inner.Export(ctx, batchSize, fn)
+}
+`, structName)
+}
+
+// exportServiceSource renders the service's Export<Domain>s; prologue holds
+// the span and feature flag gate the other methods start with
+func exportServiceSource(domainName, structName, errResult, prologue, internalErr string) (method, source string) {
+	method = fmt.Sprintf("\n\tExport%[1]ss(ctx context.Context, fn func([]model.%[1]s) error) error", structName)
+	source = fmt.Sprintf(`
+// %[1]sExportBatchSize is the number of %[1]ss Export%[2]ss reads per query
+const %[1]sExportBatchSize = %[6]d
+
+// Export%[2]ss calls fn with every %[1]s, %[1]sExportBatchSize at a time
+func (s *%[1]sService) Export%[2]ss(ctx context.Context, fn func([]model.%[2]s) error) %[3]s {
+%[4]s	if err := s.repo.Export(ctx, %[1]sExportBatchSize, fn); err != nil {
+		return %[5]s
+	}
+	return nil
+}
+`, domainName, structName, errResult, prologue, internalErr, exportBatchSize)
+	return method, source
+}
+
+// exportColumns returns the CSV header of an export and the expressions
+// formatting each column of the model named varName
+func exportColumns(varName string, fields []fieldSpec) (header, values []string, stdImports []string) {
+	header = []string{jsonName("id")}
+	values = []string{varName + ".ID.String()"}
+	stdImports = []string{}
+	use := func(path string) {
+		if !slices.Contains(stdImports, path) {
+			stdImports = append(stdImports, path)
+		}
+	}
+
+	for _, field := range fields {
+		value := varName + "." + field.GoName
+		switch field.GoType {
+		case "string":
+		case "int":
+			value = "strconv.Itoa(" + value + ")"
+			use("strconv")
+		case "int64":
+			value = "strconv.FormatInt(" + value + ", 10)"
+			use("strconv")
+		case "uint":
+			value = "strconv.FormatUint(uint64(" + value + "), 10)"
+			use("strconv")
+		case "float64":
+			value = "strconv.FormatFloat(" + value + ", 'f', -1, 64)"
+			use("strconv")
+		case "bool":
+			value = "strconv.FormatBool(" + value + ")"
+			use("strconv")
+		case "time.Time":
+			value += ".UTC().Format(time.RFC3339)"
+			use("time")
+		case "uuid.UUID":
+			value += ".String()"
+		default:
+			// Enum types are strings named after the model and field
+			value = "string(" + value + ")"
+		}
+		header = append(header, jsonName(field.Name))
+		values = append(values, value)
+	}
+
+	if !noTimestamps {
+		use("time")
+		header = append(header, jsonName("created_at"), jsonName("updated_at"))
+		values = append(values,
+			varName+".CreatedAt.UTC().Format(time.RFC3339)",
+			varName+".UpdatedAt.UTC().Format(time.RFC3339)")
+	}
+	return header, values, stdImports
+}
+
+// exportHandlerSource renders the handler's Export<Domain>s streaming the
+// domain as CSV or NDJSON, and returns the standard library imports it needs
+func exportHandlerSource(domainName, structName, varName string, fields []fieldSpec, doc, authorization string) (string, []string) {
+	header, values, stdImports := exportColumns(varName, fields)
+	stdImports = append(stdImports, "encoding/csv", "encoding/json", "strings")
+
+	quoted := make([]string, len(header))
+	for i, column := range header {
+		quoted[i] = fmt.Sprintf("%q", column)
+	}
+
+	return fmt.Sprintf(`
+// %[1]sCSVHeader names the columns of a CSV export, in %[1]sCSVRecord order
+var %[1]sCSVHeader = []string{%[4]s}
+
+// %[1]sCSVRecord formats a %[1]s as a row of a CSV export
+func %[1]sCSVRecord(%[3]s *model.%[2]s) []string {
+	return []string{
+		%[5]s,
+	}
+}
+
+// Export%[2]ss handles GET /%[1]ss/export requests, streaming every %[1]s
+// as CSV, or as NDJSON when the Accept header asks for application/x-ndjson.
+// Rows are written batch by batch as the service reads them. Once the first
+// batch is sent the status can no longer change, so a later failure ends the
+// body early and is recorded on the context with c.Error.
+%[6]sfunc (h *%[1]sHandler) Export%[2]ss(c *gin.Context) {
+%[7]s	ndjson := strings.Contains(c.GetHeader("Accept"), "application/x-ndjson")
+	csvWriter := csv.NewWriter(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+
+	started := false
+	start := func() error {
+		started = true
+		if ndjson {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Header("Content-Disposition", `+"`"+`attachment; filename="%[1]ss.ndjson"`+"`"+`)
+			c.Status(http.StatusOK)
+			return nil
+		}
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `+"`"+`attachment; filename="%[1]ss.csv"`+"`"+`)
+		c.Status(http.StatusOK)
+		return csvWriter.Write(%[1]sCSVHeader)
+	}
+
+	err := h.%[1]sService.Export%[2]ss(c.Request.Context(), func(%[3]ss []model.%[2]s) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for i := range %[3]ss {
+			var err error
+			if ndjson {
+				err = encoder.Encode(%[3]ss[i].ToResponse())
+			} else {
+				err = csvWriter.Write(%[1]sCSVRecord(&%[3]ss[i]))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil && !started {
+		httputil.RespondError(c, err)
+		return
+	}
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// An empty table still answers with the CSV header
+	if !started {
+		if err := start(); err != nil {
+			_ = c.Error(err)
+			return
+		}
+		csvWriter.Flush()
+	}
+}
+`, domainName, structName, varName, strings.Join(quoted, ", "),
+		strings.Join(values, ",\n\t\t"), doc, authorization), stdImports
+}
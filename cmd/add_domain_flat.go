@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+// layerQualifier matches references to the layer packages of a layered domain
+var layerQualifier = regexp.MustCompile(`\b(model|repository|service)\.`)
+
+// addFlatDomain generates all layers of a domain into pkg/<domain>/<domain>.go
+func addFlatDomain(domainName, moduleName string, fields []fieldSpec, opts domainOptions, migrationFiles []string) error {
+	if !token.IsIdentifier(domainName) || token.IsKeyword(domainName) {
+		return fmt.Errorf("--flat needs a domain name that is a valid package name, got %q", domainName)
+	}
+
+	sections := [][2]string{{"model", modelSource(domainName, moduleName, fields, opts)}}
+	if opts.QueryBuilder {
+		sections = append(sections, [2]string{"model", querySource(domainName, moduleName, fields)})
+	}
+	sections = append(sections, [2]string{"repository", repositorySource(domainName, moduleName, opts)})
+	if withCache {
+		sections = append(sections, [2]string{"repository", cachedRepositorySource(domainName, moduleName, opts)})
+	}
+	sections = append(sections,
+		[2]string{"service", serviceSource(domainName, moduleName, opts)},
+		[2]string{"handler", handlerSource(domainName, moduleName, fields, opts)},
+	)
+
+	content, err := flattenSources(domainName, moduleName, sections)
+	if err != nil {
+		return err
+	}
+
+	fileName := filepath.Join("pkg", domainName, domainName+".go")
+	if err := writeFile(fileName, content); err != nil {
+		return err
+	}
+
+	var testFileName string
+	if withHandlerTests {
+		tests, err := handlerTestSource(domainName, moduleName, fields, opts)
+		if err != nil {
+			return err
+		}
+		testFileName = filepath.Join("pkg", domainName, domainName+"_handler_test.go")
+		if err := writeFile(testFileName, tests); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("✅ Domain %s added successfully!\n", domainName)
+	fmt.Printf("\nGenerated files:\n")
+	fmt.Printf("  %s\n", fileName)
+	if testFileName != "" {
+		fmt.Printf("  %s\n", testFileName)
+	}
+	for _, fileName := range migrationFiles {
+		fmt.Printf("  %s\n", fileName)
+	}
+	if migrations == "automigrate" {
+		printAutoMigrateUsage(domainName + "." + capitalize(domainName))
+	}
+
+	return nil
+}
+
+// flattenSources merges layer sources into one package: imports are combined,
+// imports of the domain's own layers dropped along with their qualifiers, and
+// each layer is introduced by a section marker
+func flattenSources(domainName, moduleName string, sections [][2]string) (string, error) {
+	domainPrefix := moduleName + "/pkg/" + domainName + "/"
+	imports := make(map[string]string)
+	var body strings.Builder
+
+	for _, section := range sections {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "", section[1], parser.ParseComments)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse generated %s: %w", section[0], err)
+		}
+
+		for _, spec := range file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if strings.HasPrefix(path, domainPrefix) {
+				continue
+			}
+			line := spec.Path.Value
+			if spec.Name != nil {
+				line = spec.Name.Name + " " + line
+			}
+			imports[path] = line
+		}
+
+		code := section[1][fset.Position(lastImportEnd(file)).Offset:]
+		fmt.Fprintf(&body, "\n// %s %s\n%s", lint.SectionMarker, section[0], layerQualifier.ReplaceAllString(code, ""))
+	}
+
+	// Group imports the way goimports does: standard library, third party, module
+	groups := make([][]string, 3)
+	for path, line := range imports {
+		group := 1
+		switch {
+		case strings.HasPrefix(path, moduleName+"/"):
+			group = 2
+		case !strings.Contains(strings.SplitN(path, "/", 2)[0], "."):
+			group = 0
+		}
+		groups[group] = append(groups[group], line)
+	}
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "package %s\n\nimport (\n", domainName)
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return importPathOf(group[i]) < importPathOf(group[j]) })
+		for _, line := range group {
+			header.WriteString("\t" + line + "\n")
+		}
+		header.WriteString("\n")
+	}
+	header.WriteString(")\n")
+
+	formatted, err := format.Source([]byte(header.String() + body.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format flat domain %s: %w", domainName, err)
+	}
+	return string(formatted), nil
+}
+
+func importPathOf(line string) string {
+	return line[strings.Index(line, `"`):]
+}
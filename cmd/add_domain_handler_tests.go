@@ -0,0 +1,598 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// serviceMethod is a method of the generated service interface, as the
+// handler test mock implements it
+type serviceMethod struct {
+	Name      string
+	Field     string // mock field holding the implementation, e.g. getUser
+	Signature string // func type of the field
+	Args      string // parameter names, comma separated
+}
+
+// serviceMethods parses the service interface gear generates for a domain,
+// so the mock of the handler tests implements it exactly whatever options
+// shaped it
+func serviceMethods(domainName, moduleName string, opts domainOptions) ([]serviceMethod, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", serviceSource(domainName, moduleName, opts), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated service: %w", err)
+	}
+
+	interfaceName := capitalize(domainName) + "Service"
+	var methods []serviceMethod
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != interfaceName {
+			return true
+		}
+		for _, field := range spec.Type.(*ast.InterfaceType).Methods.List {
+			funcType := field.Type.(*ast.FuncType)
+			var args []string
+			for i, param := range funcType.Params.List {
+				if len(param.Names) == 0 {
+					param.Names = []*ast.Ident{ast.NewIdent("arg" + strconv.Itoa(i))}
+				}
+				for _, name := range param.Names {
+					args = append(args, name.Name)
+				}
+			}
+
+			var signature bytes.Buffer
+			printer.Fprint(&signature, fset, funcType)
+			name := field.Names[0].Name
+			methods = append(methods, serviceMethod{
+				Name:      name,
+				Field:     strings.ToLower(name[:1]) + name[1:],
+				Signature: signature.String(),
+				Args:      strings.Join(args, ", "),
+			})
+		}
+		return false
+	})
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("generated service declares no %s interface", interfaceName)
+	}
+	return methods, nil
+}
+
+// sampleValue returns a Go expression of a value passing the field's
+// validation, for the request bodies of the handler tests
+func (f fieldSpec) sampleValue() string {
+	switch {
+	case len(f.Enum) > 0:
+		return strconv.Quote(f.Enum[0])
+	case f.Email:
+		return `"user@example.com"`
+	}
+
+	switch f.GoType {
+	case "string":
+		value := "example"
+		if minimum, err := strconv.Atoi(f.Min); err == nil && len(value) < minimum {
+			value = strings.Repeat("x", minimum)
+		}
+		if maximum, err := strconv.Atoi(f.Max); err == nil && len(value) > maximum {
+			value = strings.Repeat("x", maximum)
+		}
+		if f.Size > 0 && len(value) > f.Size {
+			value = value[:f.Size]
+		}
+		return strconv.Quote(value)
+	case "bool":
+		return "true"
+	case "time.Time":
+		return "time.Now()"
+	case "uuid.UUID":
+		return "uuid.New()"
+	}
+
+	// Numbers take their lower bound, or 1 when it allows
+	value := "1"
+	if f.Min != "" {
+		value = f.Min
+	} else if f.Max != "" {
+		if maximum, err := strconv.ParseFloat(f.Max, 64); err == nil && maximum < 1 {
+			value = f.Max
+		}
+	}
+	return value
+}
+
+// handlerTestSource generates the httptest tests of a domain's handler: each
+// endpoint is served by a gin test engine whose handler calls a mock service
+func handlerTestSource(domainName, moduleName string, fields []fieldSpec, opts domainOptions) (string, error) {
+	structName := capitalize(domainName)
+	methods, err := serviceMethods(domainName, moduleName, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Flat domains test the handler from inside the domain package
+	packageName, modelQualifier, serviceQualifier := "handler", "model.", "service."
+	if flatDomain {
+		packageName, modelQualifier, serviceQualifier = domainName, "", ""
+	}
+	qualify := func(source string) string {
+		if flatDomain {
+			source = layerQualifier.ReplaceAllString(source, "")
+		}
+		return source
+	}
+
+	signatures := make(map[string]string)
+	var mockRows [][]string
+	var mockMethods strings.Builder
+	mockRows = append(mockRows, []string{"t", "*testing.T"})
+	for _, method := range methods {
+		signature := qualify(method.Signature)
+		signatures[method.Name] = signature
+		mockRows = append(mockRows, []string{method.Field, signature})
+		fmt.Fprintf(&mockMethods, `
+func (m *mock%[1]sService) %[2]s%[3]s {
+	if m.%[4]s == nil {
+		m.t.Fatalf("unexpected call to %[2]s")
+	}
+	return m.%[4]s(%[5]s)
+}
+`, structName, method.Name, strings.TrimPrefix(signature, "func"), method.Field, method.Args)
+	}
+
+	stdImports := []string{"context", "encoding/json", "net/http", "net/http/httptest", "strings", "testing"}
+	thirdPartyImports := []string{"github.com/gin-gonic/gin", "github.com/google/uuid"}
+	moduleImports := []string{moduleName + "/internal/errors"}
+	if !flatDomain {
+		moduleImports = append(moduleImports,
+			moduleName+"/pkg/"+domainName+"/model",
+			moduleName+"/pkg/"+domainName+"/service",
+		)
+	}
+
+	// The handler's other dependencies are left unused by the tests: no
+	// request carries an Idempotency-Key, every actor is allowed, and no
+	// test reaches the rate limit
+	constructorArgs := []string{"svc"}
+	if withAuthz {
+		moduleImports = append(moduleImports, moduleName+"/internal/authz")
+		constructorArgs = append(constructorArgs, "authz.NewAllowAll()")
+	}
+	if idempotentCreate {
+		constructorArgs = append(constructorArgs, "nil")
+	}
+	if withUserRateLimit {
+		stdImports = append(stdImports, "time")
+		moduleImports = append(moduleImports, moduleName+"/internal/ratelimit")
+		constructorArgs = append(constructorArgs, "ratelimit.NewInMemoryStore(100, time.Minute)")
+	}
+
+	var requestRows [][]string
+	for _, field := range fields {
+		value := field.sampleValue()
+		if value == "time.Now()" && !slices.Contains(stdImports, "time") {
+			stdImports = append(stdImports, "time")
+		}
+		requestRows = append(requestRows, []string{field.GoName + ":", value + ","})
+	}
+
+	listCheck := fmt.Sprintf(`			var responses []%[1]s%[2]sResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+				t.Fatalf("invalid JSON response: %%v", err)
+			}
+			if len(responses) != 2 {
+				t.Errorf("got %%d %[3]ss, want 2", len(responses))
+			}
+`, modelQualifier, structName, domainName)
+	listReturn := "return []" + modelQualifier + structName + "{{ID: uuid.New()}, {ID: uuid.New()}}, nil"
+	listFailure := "return nil, errors.ErrInternalInstance"
+	if cursorPagination {
+		moduleImports = append(moduleImports, moduleName+"/internal/pagination")
+		listCheck = fmt.Sprintf(`			var page pagination.Page[%[1]s%[2]sResponse]
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatalf("invalid JSON response: %%v", err)
+			}
+			if len(page.Items) != 2 {
+				t.Errorf("got %%d %[3]ss, want 2", len(page.Items))
+			}
+`, modelQualifier, structName, domainName)
+		listReturn = "return []" + modelQualifier + structName + "{{ID: uuid.New()}, {ID: uuid.New()}}, nil, nil"
+		listFailure = "return nil, nil, errors.ErrInternalInstance"
+	}
+	if linkPagination {
+		listReturn = "return []" + modelQualifier + structName + "{{ID: uuid.New()}, {ID: uuid.New()}}, 2, nil"
+		listFailure = "return nil, 0, errors.ErrInternalInstance"
+	}
+
+	var patchTest string
+	if withPatch {
+		patchTest = fmt.Sprintf(`
+func TestPatch%[1]s(t *testing.T) {
+	id := uuid.New()
+	patched := %[3]s {
+		return &%[4]s%[1]s{ID: id}, nil
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		patch      %[3]s
+		wantStatus int
+	}{
+		{"patched", "/%[2]ss/" + id.String(), "{}", patched, http.StatusOK},
+		{"invalid id", "/%[2]ss/not-a-uuid", "{}", nil, http.StatusBadRequest},
+		{"malformed body", "/%[2]ss/" + id.String(), "{", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, patch%[1]s: tt.patch})
+			w := serve%[1]s(router, http.MethodPatch, tt.path, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+`, structName, domainName, signatures["Patch"+structName], modelQualifier)
+	}
+
+	var bulkTests string
+	if withBulk {
+		bulkTests = fmt.Sprintf(`
+func TestCreate%[1]ss(t *testing.T) {
+	created := %[3]s {
+		for i := range %[2]ss {
+			%[2]ss[i].ID = uuid.New()
+		}
+		return %[2]ss, nil
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		create     %[3]s
+		wantStatus int
+	}{
+		{"created", "[" + valid%[1]sRequest(t) + "]", created, http.StatusCreated},
+		{"empty", "[]", nil, http.StatusBadRequest},
+		{"malformed body", "[", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, create%[1]ss: tt.create})
+			w := serve%[1]s(router, http.MethodPost, "/%[5]ss/bulk", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestDelete%[1]ss(t *testing.T) {
+	deleted := %[4]s {
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		delete     %[4]s
+		wantStatus int
+	}{
+		{"deleted", "[\"" + uuid.NewString() + "\"]", deleted, http.StatusNoContent},
+		{"empty", "[]", nil, http.StatusBadRequest},
+		{"invalid id", "[\"not-a-uuid\"]", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, delete%[1]ss: tt.delete})
+			w := serve%[1]s(router, http.MethodDelete, "/%[5]ss/bulk", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+`, structName, safeVarName(domainName), signatures["Create"+structName+"s"],
+			signatures["Delete"+structName+"s"], domainName)
+	}
+
+	var exportTest string
+	if withExport {
+		exportTest = fmt.Sprintf(`
+func TestExport%[1]ss(t *testing.T) {
+	exported := %[3]s {
+		return fn([]%[4]s%[1]s{{ID: uuid.New()}, {ID: uuid.New()}})
+	}
+	failed := %[3]s {
+		return errors.ErrInternalInstance
+	}
+
+	tests := []struct {
+		name            string
+		accept          string
+		export          %[3]s
+		wantStatus      int
+		wantContentType string
+		wantLines       int
+	}{
+		{"csv", "text/csv", exported, http.StatusOK, "text/csv; charset=utf-8", 3},
+		{"ndjson", "application/x-ndjson", exported, http.StatusOK, "application/x-ndjson", 2},
+		{"service error", "", failed, http.StatusInternalServerError, "application/json; charset=utf-8", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, export%[1]ss: tt.export})
+			request := httptest.NewRequest(http.MethodGet, "/%[2]ss/export", nil)
+			request.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %%q, want %%q", got, tt.wantContentType)
+			}
+			if lines := strings.Count(w.Body.String(), "\n"); lines != tt.wantLines {
+				t.Errorf("got %%d lines, want %%d: %%s", lines, tt.wantLines, w.Body)
+			}
+		})
+	}
+}
+`, structName, domainName, signatures["Export"+structName+"s"], modelQualifier)
+	}
+
+	sort.Strings(stdImports)
+	sort.Strings(moduleImports)
+	imports := func(paths []string) string {
+		return "\t\"" + strings.Join(paths, "\"\n\t\"") + "\""
+	}
+
+	content := fmt.Sprintf(`package %[3]s
+
+import (
+%[4]s
+
+%[5]s
+
+%[6]s
+)
+
+// mock%[1]sService implements %[8]s%[1]sService with a replaceable func per
+// method; calling one left nil fails the test
+type mock%[1]sService struct {
+%[7]s}
+
+var _ %[8]s%[1]sService = (*mock%[1]sService)(nil)
+%[9]s
+// new%[1]sTestRouter registers a %[1]sHandler backed by svc on a gin test engine
+func new%[1]sTestRouter(svc *mock%[1]sService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	_, router := gin.CreateTestContext(httptest.NewRecorder())
+	New%[1]sHandler(%[10]s).RegisterRoutes(router)
+	return router
+}
+
+// serve%[1]s sends a request, with body as JSON when not empty, through router
+func serve%[1]s(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+	return w
+}
+
+// valid%[1]sRequest returns a %[2]s request body passing validation
+func valid%[1]sRequest(t *testing.T) string {
+	t.Helper()
+	body, err := json.Marshal(%[11]s%[1]sRequest{
+%[12]s	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestGet%[1]s(t *testing.T) {
+	id := uuid.New()
+	found := %[13]s {
+		return &%[11]s%[1]s{ID: id}, nil
+	}
+	missing := %[13]s {
+		return nil, errors.ErrNotFoundInstance
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		get        %[13]s
+		wantStatus int
+	}{
+		{"found", "/%[2]ss/" + id.String(), found, http.StatusOK},
+		{"invalid id", "/%[2]ss/not-a-uuid", nil, http.StatusBadRequest},
+		{"not found", "/%[2]ss/" + id.String(), missing, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, get%[1]s: tt.get})
+			w := serve%[1]s(router, http.MethodGet, tt.path, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var response %[11]s%[1]sResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("invalid JSON response: %%v", err)
+			}
+			if response.ID != id {
+				t.Errorf("id = %%s, want %%s", response.ID, id)
+			}
+		})
+	}
+}
+
+func TestCreate%[1]s(t *testing.T) {
+	id := uuid.New()
+	created := %[14]s {
+		%[17]s.ID = id
+		return &%[17]s, nil
+	}
+	failed := %[14]s {
+		return nil, errors.ErrInternalInstance
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		create     %[14]s
+		wantStatus int
+	}{
+		{"created", valid%[1]sRequest(t), created, http.StatusCreated},
+		{"empty body", "", nil, http.StatusBadRequest},
+		{"malformed body", "{", nil, http.StatusBadRequest},
+		{"service error", valid%[1]sRequest(t), failed, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, create%[1]s: tt.create})
+			w := serve%[1]s(router, http.MethodPost, "/%[2]ss", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+			if w.Code == http.StatusCreated && !strings.HasSuffix(w.Header().Get("Location"), "/"+id.String()) {
+				t.Errorf("Location = %%q, want the created %[2]s's URL", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestUpdate%[1]s(t *testing.T) {
+	id := uuid.New()
+	updated := %[15]s {
+		return %[17]s, nil
+	}
+	missing := %[15]s {
+		return nil, errors.ErrNotFoundInstance
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		update     %[15]s
+		wantStatus int
+	}{
+		{"updated", "/%[2]ss/" + id.String(), updated, http.StatusOK},
+		{"invalid id", "/%[2]ss/not-a-uuid", nil, http.StatusBadRequest},
+		{"not found", "/%[2]ss/" + id.String(), missing, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, update%[1]s: tt.update})
+			w := serve%[1]s(router, http.MethodPut, tt.path, valid%[1]sRequest(t))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var response %[11]s%[1]sResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("invalid JSON response: %%v", err)
+			}
+			if response.ID != id {
+				t.Errorf("id = %%s, want the id of the path %%s", response.ID, id)
+			}
+		})
+	}
+}
+
+func TestDelete%[1]s(t *testing.T) {
+	id := uuid.New()
+	deleted := %[16]s {
+		return nil
+	}
+	missing := %[16]s {
+		return errors.ErrNotFoundInstance
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		delete     %[16]s
+		wantStatus int
+	}{
+		{"deleted", "/%[2]ss/" + id.String(), deleted, http.StatusNoContent},
+		{"invalid id", "/%[2]ss/not-a-uuid", nil, http.StatusBadRequest},
+		{"not found", "/%[2]ss/" + id.String(), missing, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, delete%[1]s: tt.delete})
+			w := serve%[1]s(router, http.MethodDelete, tt.path, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestList%[1]ss(t *testing.T) {
+	listed := %[18]s {
+		%[19]s
+	}
+	failed := %[18]s {
+		%[20]s
+	}
+
+	tests := []struct {
+		name       string
+		list       %[18]s
+		wantStatus int
+	}{
+		{"listed", listed, http.StatusOK},
+		{"service error", failed, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := new%[1]sTestRouter(&mock%[1]sService{t: t, list%[1]ss: tt.list})
+			w := serve%[1]s(router, http.MethodGet, "/%[2]ss", "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", w.Code, tt.wantStatus, w.Body)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+%[21]s		})
+	}
+}
+%[22]s%[23]s%[24]s`, structName, domainName, packageName,
+		imports(stdImports), imports(thirdPartyImports), imports(moduleImports),
+		alignColumns(mockRows, "\t"), serviceQualifier, mockMethods.String(),
+		strings.Join(constructorArgs, ", "), modelQualifier, alignColumns(requestRows, "\t\t"),
+		signatures["Get"+structName], signatures["Create"+structName],
+		signatures["Update"+structName], signatures["Delete"+structName],
+		safeVarName(domainName), signatures["List"+structName+"s"],
+		listReturn, listFailure, listCheck, patchTest, bulkTests, exportTest)
+
+	return content, nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// listParamsSource renders the <Domain>ListParams of --list-params: a field
+// per filter bound from its query parameter, order_by, offset and limit,
+// with binding tags gin validates them against, and the Query method
+// turning them into a <Domain>Query
+func listParamsSource(domainName string, fields []fieldSpec, orderKeys []string) string {
+	structName := capitalize(domainName)
+	enums := make(map[string][]string)
+	for _, field := range fields {
+		if len(field.Enum) > 0 {
+			enums[field.GoType] = field.Enum
+		}
+	}
+
+	filters := queryFilters(domainName, fields)
+	var rows [][]string
+	var apply strings.Builder
+	for _, filter := range filters {
+		name := strings.TrimPrefix(filter.Method, "Where")
+		goType, binding := filter.GoType, ""
+		switch filter.GoType {
+		case "string":
+			fmt.Fprintf(&apply, "\tif p.%[1]s != \"\" {\n\t\tquery.%[2]s(p.%[1]s)\n\t}\n", name, filter.Method)
+		case "uuid.UUID":
+			// gin cannot bind a UUID, so it is checked as a string and parsed
+			goType, binding = "string", ` binding:"omitempty,uuid"`
+			fmt.Fprintf(&apply, `	if p.%[1]s != "" {
+		id, err := uuid.Parse(p.%[1]s)
+		if err != nil {
+			return nil, errors.ErrInvalidInstance.WithVariables(map[string]string{
+				"field": %[2]q,
+				"rule":  "uuid",
+			}).WithError(err)
+		}
+		query.%[3]s(id)
+	}
+`, name, filter.Param, filter.Method)
+		case "bool", "int", "int64", "uint", "float64", "time.Time":
+			// Pointers tell an absent parameter from a zero value
+			goType = "*" + filter.GoType
+			fmt.Fprintf(&apply, "\tif p.%[1]s != nil {\n\t\tquery.%[2]s(*p.%[1]s)\n\t}\n", name, filter.Method)
+		default:
+			// Enum types are strings named after the model and field
+			binding = fmt.Sprintf(` binding:"omitempty,oneof=%s"`, strings.Join(enums[filter.GoType], " "))
+			fmt.Fprintf(&apply, "\tif p.%[1]s != \"\" {\n\t\tquery.%[2]s(p.%[1]s)\n\t}\n", name, filter.Method)
+		}
+		rows = append(rows, []string{name, goType, fmt.Sprintf("`form:%q%s`", filter.Param, binding)})
+	}
+
+	orderValues := slices.Clone(orderKeys)
+	for _, key := range orderKeys {
+		orderValues = append(orderValues, "-"+key)
+	}
+	rows = append(rows,
+		[]string{"OrderBy", "string", fmt.Sprintf("`form:\"order_by\" binding:\"omitempty,oneof=%s\"`", strings.Join(orderValues, " "))},
+		[]string{"Offset", "*int", "`form:\"offset\" binding:\"omitempty,min=0\"`"},
+		[]string{"Limit", "*int", "`form:\"limit\" binding:\"omitempty,min=0,max=100\"`"},
+	)
+
+	return fmt.Sprintf(`
+// %[2]sListParams are the query parameters of a List request, bound with
+// gin's ShouldBindQuery and checked against their binding tags:
+//
+//	GET /%[1]ss?%[6]s=<value>&order_by=-%[4]s&offset=0&limit=20
+type %[2]sListParams struct {
+%[3]s}
+
+// Query returns the %[2]sQuery the parameters describe. A page needs only
+// one of offset and limit: offset defaults to 0 and limit to
+// Max%[2]sPageSize.
+func (p %[2]sListParams) Query() (*%[2]sQuery, error) {
+	query := New%[2]sQuery()
+%[5]s
+	if p.OrderBy != "" {
+		field, desc := strings.CutPrefix(p.OrderBy, "-")
+		query.OrderBy(field, desc)
+	}
+
+	if p.Offset != nil || p.Limit != nil {
+		offset, limit := 0, Max%[2]sPageSize
+		if p.Offset != nil {
+			offset = *p.Offset
+		}
+		if p.Limit != nil {
+			limit = *p.Limit
+		}
+		query.Paginate(offset, limit)
+	}
+
+	return query, nil
+}
+`, domainName, structName, alignColumns(rows, "\t"), orderKeys[0], apply.String(), filters[0].Param)
+}
+
+// bindListParamsSource renders the statements of a List handler binding
+// its query parameters into the model's <Domain>ListParams
+func bindListParamsSource(structName string) string {
+	return fmt.Sprintf(`	var params model.%[1]sListParams
+	if err := httputil.BindQuery(c, &params); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+`, structName)
+}
+
+// generateBindQueryHelper writes internal/httputil/query.go, through which
+// --list-params handlers bind their query parameters
+func generateBindQueryHelper(moduleName string) error {
+	content := fmt.Sprintf(`package httputil
+
+import (
+	stderrors "errors"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"%[1]s/internal/errors"
+)
+
+// BindQuery binds the query parameters of c into dst, a pointer to a struct
+// with form tags, and checks its binding tags. Its ErrInvalidInstance names
+// the parameter in field and the failed tag in rule ("oneof=a b",
+// "max=100"), or has rule "type" for a value that does not parse.
+func BindQuery(c *gin.Context, dst any) error {
+	err := c.ShouldBindQuery(dst)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if stderrors.As(err, &validationErrs) {
+		fieldErr := validationErrs[0]
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule += "=" + fieldErr.Param()
+		}
+		return errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": queryParam(dst, fieldErr.StructField()),
+			"rule":  rule,
+		}).WithError(err)
+	}
+	return errors.ErrInvalidInstance.WithVariables(map[string]string{
+		"field": "query",
+		"rule":  "type",
+	}).WithError(err)
+}
+
+// queryParam returns the query parameter of the named field of dst, its
+// form tag, or the field name when it has none
+func queryParam(dst any, fieldName string) string {
+	field, ok := reflect.TypeOf(dst).Elem().FieldByName(fieldName)
+	if tag := field.Tag.Get("form"); ok && tag != "" {
+		return tag
+	}
+	return fieldName
+}
+`, moduleName)
+
+	return writeFile(filepath.Join("internal", "httputil", "query.go"), content)
+}
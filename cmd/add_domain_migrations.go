@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// migrationsDir holds the versioned SQL migrations of --migrations sql
+const migrationsDir = "migrations"
+
+// migrationFilePattern matches golang-migrate file names, e.g. 0001_create_users.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_.+\.(up|down)\.sql$`)
+
+// sqlTypes maps model field Go types to the PostgreSQL column types gorm's
+// AutoMigrate would create; fields with a Column or Size override them
+var sqlTypes = map[string]string{
+	"string":    "text",
+	"int":       "bigint",
+	"int64":     "bigint",
+	"uint":      "bigint",
+	"float64":   "decimal",
+	"bool":      "boolean",
+	"time.Time": "timestamptz",
+	"uuid.UUID": "uuid",
+}
+
+// generateMigration writes the up and down migrations creating the table of
+// a domain, numbered after the highest version in migrations/, and returns
+// their paths
+func generateMigration(domainName string, fields []fieldSpec, relations []relationSpec) ([]string, error) {
+	version, err := nextMigrationVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	table := toSnakeCase(domainName) + "s"
+	columns := []string{"id uuid PRIMARY KEY DEFAULT gen_random_uuid()"}
+	var indexes []string
+	for _, field := range fields {
+		columns = append(columns, field.Name+" "+field.sqlColumn())
+
+		switch {
+		case field.Unique:
+			indexes = append(indexes, fmt.Sprintf("CREATE UNIQUE INDEX idx_%[1]s_%[2]s ON %[1]s (%[2]s);\n", table, field.Name))
+		case field.Index:
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX idx_%[1]s_%[2]s ON %[1]s (%[2]s);\n", table, field.Name))
+		}
+	}
+	for _, relation := range relations {
+		if relation.HasMany {
+			continue
+		}
+		foreignKey := relation.foreignKeyField()
+		for i, field := range fields {
+			if strings.EqualFold(field.GoName, foreignKey.GoName) {
+				columns[i+1] += fmt.Sprintf(" REFERENCES %ss (id)", toSnakeCase(relation.Domain))
+			}
+		}
+	}
+	if !noTimestamps {
+		columns = append(columns, "created_at timestamptz", "updated_at timestamptz")
+	}
+
+	up := fmt.Sprintf("CREATE TABLE %s (\n    %s\n);\n", table, strings.Join(columns, ",\n    "))
+	if len(indexes) > 0 {
+		up += "\n" + strings.Join(indexes, "")
+	}
+	down := fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", table)
+
+	name := filepath.Join(migrationsDir, fmt.Sprintf("%04d_create_%s", version, table))
+	paths := []string{name + ".up.sql", name + ".down.sql"}
+	for i, content := range []string{up, down} {
+		if err := writeFile(paths[i], content); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// nextMigrationVersion returns the version after the highest one in
+// migrations/, 1 when there is none
+func nextMigrationVersion() (int, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if os.IsNotExist(err) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", migrationsDir, err)
+	}
+
+	latest := 0
+	for _, entry := range entries {
+		if match := migrationFilePattern.FindStringSubmatch(entry.Name()); match != nil {
+			version, err := strconv.Atoi(match[1])
+			if err == nil && version > latest {
+				latest = version
+			}
+		}
+	}
+	return latest + 1, nil
+}
+
+// sqlColumn renders the column type and constraints of a field, matching
+// its gorm tag
+func (f fieldSpec) sqlColumn() string {
+	column := sqlTypes[f.GoType]
+	switch {
+	case f.Column != "":
+		column = f.Column
+	case f.Size > 0:
+		column = fmt.Sprintf("varchar(%d)", f.Size)
+	}
+	if f.Required {
+		column += " NOT NULL"
+	}
+	return column
+}
+
+// generateAutoMigrate writes internal/database/migrate.go, shared by the
+// domains of --migrations automigrate, and returns its path when it was
+// written
+func generateAutoMigrate() ([]string, error) {
+	fileName := filepath.Join("internal", "database", "migrate.go")
+	if _, err := os.Stat(fileName); err == nil {
+		return nil, nil
+	}
+
+	content := `package database
+
+import (
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// AutoMigrate creates the tables of models and adds their missing columns.
+// gorm never drops columns, which keeps it safe to run on every start but
+// hides the columns models no longer have: with reportDrift, as in
+// development, the differences between models and schema are logged first.
+func AutoMigrate(db *gorm.DB, reportDrift bool, models ...any) error {
+	if reportDrift {
+		for _, model := range models {
+			if err := ReportDrift(db, slog.Default(), model); err != nil {
+				return err
+			}
+		}
+	}
+	return db.AutoMigrate(models...)
+}
+
+// ReportDrift logs a warning for each difference between a model and its
+// table in the database: a missing table or column, which AutoMigrate
+// creates, and a column no field of the model maps to, which it keeps. It
+// changes nothing.
+func ReportDrift(db *gorm.DB, logger *slog.Logger, model any) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return fmt.Errorf("failed to parse model %T: %w", model, err)
+	}
+	table := stmt.Schema.Table
+
+	migrator := db.Migrator()
+	if !migrator.HasTable(model) {
+		logger.Warn("schema drift: table missing, AutoMigrate will create it", "table", table)
+		return nil
+	}
+
+	columnTypes, err := migrator.ColumnTypes(model)
+	if err != nil {
+		return fmt.Errorf("failed to read the columns of %s: %w", table, err)
+	}
+	columns := make(map[string]bool, len(columnTypes))
+	for _, column := range columnTypes {
+		columns[column.Name()] = true
+	}
+
+	fields := make(map[string]bool, len(stmt.Schema.DBNames))
+	for _, name := range stmt.Schema.DBNames {
+		fields[name] = true
+		if !columns[name] {
+			logger.Warn("schema drift: column missing, AutoMigrate will add it",
+				"table", table, "column", name, "field", stmt.Schema.LookUpField(name).Name)
+		}
+	}
+	for _, column := range columnTypes {
+		if !fields[column.Name()] {
+			logger.Warn("schema drift: column not in the model, AutoMigrate keeps it",
+				"table", table, "column", column.Name())
+		}
+	}
+	return nil
+}
+`
+
+	if err := writeFile(fileName, content); err != nil {
+		return nil, err
+	}
+	return []string{fileName}, nil
+}
+
+// printAutoMigrateUsage shows how main migrates the table of a model
+func printAutoMigrateUsage(model string) {
+	fmt.Printf("\nMigrate the table on start, logging schema drift in development:\n")
+	fmt.Printf("  database.AutoMigrate(db, cfg.Environment == \"development\", &%s{})\n", model)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// memoryRepositorySource generates the repository of projects without an
+// ORM (init --minimal): the same interface, implemented over a map guarded
+// by a mutex. It returns domain errors, so the service passes them on as
+// with --repo-errors.
+func memoryRepositorySource(domainName, moduleName string) string {
+	structName := capitalize(domainName)
+	varName := safeVarName(domainName)
+
+	stdImports := []string{`"bytes"`, `"context"`, `"slices"`, `"sync"`}
+	existing, createStamps, updateStamps := "_", "", ""
+	order := "bytes.Compare(a.ID[:], b.ID[:])"
+	if !noTimestamps {
+		stdImports = append(stdImports, `"cmp"`, `"time"`)
+		existing = "existing"
+		createStamps = fmt.Sprintf("\t%[1]s.CreatedAt = time.Now().UTC()\n\t%[1]s.UpdatedAt = %[1]s.CreatedAt\n", varName)
+		updateStamps = fmt.Sprintf("\t%[1]s.CreatedAt = existing.CreatedAt\n\t%[1]s.UpdatedAt = time.Now().UTC()\n", varName)
+		order = "cmp.Or(a.CreatedAt.Compare(b.CreatedAt), bytes.Compare(a.ID[:], b.ID[:]))"
+	}
+	sort.Strings(stdImports)
+
+	return fmt.Sprintf(`package repository
+
+import (
+	%[5]s
+
+	"github.com/google/uuid"
+
+	"%[1]s/internal/errors"
+	"%[1]s/pkg/%[2]s/model"
+)
+
+// %[3]sRepository defines the interface for %[2]s data operations
+type %[3]sRepository interface {
+	Create(ctx context.Context, %[4]s model.%[3]s) (*model.%[3]s, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.%[3]s, error)
+	Update(ctx context.Context, %[4]s *model.%[3]s) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context) ([]model.%[3]s, error)
+}
+
+// %[2]sRepository keeps %[2]ss in memory; replace it with a persistent
+// implementation of %[3]sRepository when the project needs one
+type %[2]sRepository struct {
+	mu    sync.RWMutex
+	%[4]ss map[uuid.UUID]model.%[3]s
+}
+
+// New%[3]sRepository creates a new %[2]s repository instance
+func New%[3]sRepository() %[3]sRepository {
+	return &%[2]sRepository{
+		%[4]ss: make(map[uuid.UUID]model.%[3]s),
+	}
+}
+
+func (r *%[2]sRepository) Create(ctx context.Context, %[4]s model.%[3]s) (*model.%[3]s, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	%[4]s.ID = uuid.New()
+%[6]s	r.%[4]ss[%[4]s.ID] = %[4]s
+	return &%[4]s, nil
+}
+
+func (r *%[2]sRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.%[3]s, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	%[4]s, ok := r.%[4]ss[id]
+	if !ok {
+		return nil, errors.ErrNotFoundInstance
+	}
+	return &%[4]s, nil
+}
+
+func (r *%[2]sRepository) Update(ctx context.Context, %[4]s *model.%[3]s) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	%[8]s, ok := r.%[4]ss[%[4]s.ID]
+	if !ok {
+		return errors.ErrNotFoundInstance
+	}
+%[7]s	r.%[4]ss[%[4]s.ID] = *%[4]s
+	return nil
+}
+
+func (r *%[2]sRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.%[4]ss, id)
+	return nil
+}
+
+// List returns every %[2]s, sorted so repeated calls agree
+func (r *%[2]sRepository) List(ctx context.Context) ([]model.%[3]s, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	%[4]ss := make([]model.%[3]s, 0, len(r.%[4]ss))
+	for _, %[4]s := range r.%[4]ss {
+		%[4]ss = append(%[4]ss, %[4]s)
+	}
+	slices.SortFunc(%[4]ss, func(a, b model.%[3]s) int {
+		return %[9]s
+	})
+	return %[4]ss, nil
+}
+`, moduleName, domainName, structName, varName, strings.Join(stdImports, "\n\t"), createStamps, updateStamps, existing, order)
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// queryFilter is a Where method of a --query-builder query and the request
+// parameter the handler maps to it
+type queryFilter struct {
+	Method   string // WhereName
+	Param    string // JSON key of the query parameter
+	Column   string
+	Operator string
+	GoType   string
+	Doc      string
+}
+
+// queryFilters returns the filters of a domain's query: equality on each
+// field, and a range on time fields and the creation timestamp
+func queryFilters(domainName string, fields []fieldSpec) []queryFilter {
+	var filters []queryFilter
+	for _, field := range fields {
+		if field.GoType == "time.Time" {
+			filters = append(filters,
+				queryFilter{"Where" + field.GoName + "After", jsonName(field.Name + "_after"), field.Name, ">", field.GoType,
+					fmt.Sprintf("keeps the %ss whose %s is after t", domainName, field.Name)},
+				queryFilter{"Where" + field.GoName + "Before", jsonName(field.Name + "_before"), field.Name, "<", field.GoType,
+					fmt.Sprintf("keeps the %ss whose %s is before t", domainName, field.Name)},
+			)
+			continue
+		}
+		filters = append(filters, queryFilter{"Where" + field.GoName, jsonName(field.Name), field.Name, "=", field.GoType,
+			fmt.Sprintf("keeps the %ss whose %s equals value", domainName, field.Name)})
+	}
+	if !noTimestamps {
+		filters = append(filters,
+			queryFilter{"WhereCreatedAfter", jsonName("created_after"), "created_at", ">", "time.Time",
+				fmt.Sprintf("keeps the %ss created after t", domainName)},
+			queryFilter{"WhereCreatedBefore", jsonName("created_before"), "created_at", "<", "time.Time",
+				fmt.Sprintf("keeps the %ss created before t", domainName)},
+		)
+	}
+	return filters
+}
+
+// querySource renders pkg/<domain>/model/<domain>_query.go, the builder of
+// --query-builder: Where methods collecting conditions, OrderBy and
+// Paginate, read back by the repository when it compiles the gorm query
+func querySource(domainName, moduleName string, fields []fieldSpec) string {
+	structName := capitalize(domainName)
+
+	stdImports := []string{}
+	thirdPartyImports := []string{}
+	var methods strings.Builder
+	for _, filter := range queryFilters(domainName, fields) {
+		param := "value"
+		if filter.GoType == "time.Time" {
+			param = "t"
+		}
+		switch filter.GoType {
+		case "time.Time":
+			if !slices.Contains(stdImports, "time") {
+				stdImports = append(stdImports, "time")
+			}
+		case "uuid.UUID":
+			if !slices.Contains(thirdPartyImports, "github.com/google/uuid") {
+				thirdPartyImports = append(thirdPartyImports, "github.com/google/uuid")
+			}
+		}
+		fmt.Fprintf(&methods, `
+// %[2]s %[6]s
+func (q *%[1]sQuery) %[2]s(%[7]s %[5]s) *%[1]sQuery {
+	q.conditions = append(q.conditions, %[1]sCondition{Column: %[3]q, Operator: %[4]q, Value: %[7]s})
+	return q
+}
+`, structName, filter.Method, filter.Column, filter.Operator, filter.GoType, filter.Doc, param)
+	}
+
+	orderRows := [][]string{}
+	var orderKeys []string
+	for _, field := range fields {
+		orderRows = append(orderRows, []string{fmt.Sprintf("%q:", jsonName(field.Name)), fmt.Sprintf("%q,", field.Name)})
+		orderKeys = append(orderKeys, jsonName(field.Name))
+	}
+	if !noTimestamps {
+		for _, column := range []string{"created_at", "updated_at"} {
+			orderRows = append(orderRows, []string{fmt.Sprintf("%q:", jsonName(column)), fmt.Sprintf("%q,", column)})
+			orderKeys = append(orderKeys, jsonName(column))
+		}
+	}
+
+	// With --list-params the handler binds the parameters into a struct the
+	// service turns into the query
+	var listParams string
+	if withListParams {
+		listParams = listParamsSource(domainName, fields, orderKeys)
+		stdImports = append(stdImports, "strings")
+	}
+
+	return fmt.Sprintf(`package model
+
+import (
+%[4]s)
+
+// Max%[2]sPageSize bounds the limit of a %[2]sQuery page
+const Max%[2]sPageSize = 100
+
+// %[2]sQuery filters, orders and pages the %[1]ss List returns. Start from
+// New%[2]sQuery, which matches every %[1]s, and chain its methods:
+//
+//	New%[2]sQuery().OrderBy(%[6]q, true).Paginate(0, 20)
+type %[2]sQuery struct {
+	conditions []%[2]sCondition
+	orderBy    string
+	desc       bool
+	offset     int
+	limit      int
+}
+
+// %[2]sCondition is a filter of a %[2]sQuery, "Column Operator Value" in SQL
+type %[2]sCondition struct {
+	Column   string
+	Operator string
+	Value    any
+}
+
+// %[1]sOrderColumns maps the fields OrderBy accepts to their column
+var %[1]sOrderColumns = map[string]string{
+%[5]s}
+
+// New%[2]sQuery returns a query matching every %[1]s, in storage order
+func New%[2]sQuery() *%[2]sQuery {
+	return &%[2]sQuery{}
+}
+%[3]s
+// OrderBy sorts by field, named by its JSON key, descending when desc is
+// set. Validate rejects fields that are not columns of the %[1]s.
+func (q *%[2]sQuery) OrderBy(field string, desc bool) *%[2]sQuery {
+	q.orderBy, q.desc = field, desc
+	return q
+}
+
+// Paginate returns limit %[1]ss from offset; a zero limit returns them all
+func (q *%[2]sQuery) Paginate(offset, limit int) *%[2]sQuery {
+	q.offset, q.limit = offset, limit
+	return q
+}
+
+// Conditions returns the filters, in the order they were added
+func (q *%[2]sQuery) Conditions() []%[2]sCondition {
+	return q.conditions
+}
+
+// Order returns the column to sort by, "" when the query is unordered
+func (q *%[2]sQuery) Order() (column string, desc bool) {
+	return %[1]sOrderColumns[q.orderBy], q.desc
+}
+
+// Page returns the offset and limit of the page, a zero limit for all %[1]ss
+func (q *%[2]sQuery) Page() (offset, limit int) {
+	return q.offset, q.limit
+}
+
+// Validate checks the order field and the page bounds
+func (q *%[2]sQuery) Validate() error {
+	if _, ok := %[1]sOrderColumns[q.orderBy]; q.orderBy != "" && !ok {
+		return errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "order_by",
+			"rule":  %[7]q,
+		})
+	}
+	if q.offset < 0 {
+		return errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "offset",
+			"rule":  "min=0",
+		})
+	}
+	if q.limit < 0 || q.limit > Max%[2]sPageSize {
+		return errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "limit",
+			"rule":  "min=0,max=100",
+		})
+	}
+	return nil
+}
+%[8]s`, domainName, structName, methods.String(),
+		importBlock(stdImports, thirdPartyImports, []string{moduleName + "/internal/errors"}),
+		alignColumns(orderRows, "\t"), orderKeys[0], "oneof="+strings.Join(orderKeys, " "), listParams)
+}
+
+// queryScopeSource renders the repository helper compiling a query into the
+// conditions, order and page of a gorm query
+func queryScopeSource(domainName string) string {
+	structName := capitalize(domainName)
+	return fmt.Sprintf(`
+// %[1]sQueryScope compiles query to the conditions, order and page of a gorm query
+func %[1]sQueryScope(query *model.%[2]sQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, condition := range query.Conditions() {
+			db = db.Where(condition.Column+" "+condition.Operator+" ?", condition.Value)
+		}
+		if column, desc := query.Order(); column != "" {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+		}
+		if offset, limit := query.Page(); limit > 0 {
+			db = db.Offset(offset).Limit(limit)
+		}
+		return db
+	}
+}
+`, domainName, structName)
+}
+
+// queryParserSource renders the handler helper mapping the query parameters
+// of a List request to builder calls, and returns the imports it needs
+func queryParserSource(domainName string, fields []fieldSpec) (string, []string, []string) {
+	structName := capitalize(domainName)
+	stdImports := []string{"strconv", "strings"}
+	var thirdPartyImports []string
+
+	var parse strings.Builder
+	for _, filter := range queryFilters(domainName, fields) {
+		var conversion string
+		switch filter.GoType {
+		case "string":
+			fmt.Fprintf(&parse, `	if value := c.Query(%[1]q); value != "" {
+		query.%[2]s(value)
+	}
+`, filter.Param, filter.Method)
+			continue
+		case "bool":
+			conversion = "strconv.ParseBool(value)"
+		case "int":
+			conversion = "strconv.Atoi(value)"
+		case "int64":
+			conversion = "strconv.ParseInt(value, 10, 64)"
+		case "uint":
+			conversion = "strconv.ParseUint(value, 10, 0)"
+		case "float64":
+			conversion = "strconv.ParseFloat(value, 64)"
+		case "uuid.UUID":
+			conversion = "uuid.Parse(value)"
+			if !slices.Contains(thirdPartyImports, "github.com/google/uuid") {
+				thirdPartyImports = append(thirdPartyImports, "github.com/google/uuid")
+			}
+		case "time.Time":
+			conversion = "time.Parse(time.RFC3339, value)"
+			if !slices.Contains(stdImports, "time") {
+				stdImports = append(stdImports, "time")
+			}
+		default:
+			// Enum types are strings named after the model and field
+			fmt.Fprintf(&parse, `	if value := c.Query(%[1]q); value != "" {
+		query.%[2]s(model.%[3]s(value))
+	}
+`, filter.Param, filter.Method, filter.GoType)
+			continue
+		}
+
+		value := "parsed"
+		if filter.GoType == "uint" {
+			value = "uint(parsed)"
+		}
+		fmt.Fprintf(&parse, `	if value := c.Query(%[1]q); value != "" {
+		parsed, err := %[3]s
+		if err != nil {
+			return invalid(%[1]q, err)
+		}
+		query.%[2]s(%[4]s)
+	}
+`, filter.Param, filter.Method, conversion, value)
+	}
+
+	return fmt.Sprintf(`
+// parse%[2]sQuery maps the query parameters of a List request to a
+// %[2]sQuery: a parameter per filter, order_by=<field> (-<field> for
+// descending order) and offset and limit. On a malformed value it writes a
+// 400 response and returns false, so the handler only returns.
+func parse%[2]sQuery(c *gin.Context) (*model.%[2]sQuery, bool) {
+	query := model.New%[2]sQuery()
+	invalid := func(field string, err error) (*model.%[2]sQuery, bool) {
+		httputil.RespondError(c, errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": field,
+		}).WithError(err))
+		return nil, false
+	}
+
+%[3]s
+	if value := c.Query("order_by"); value != "" {
+		field, desc := strings.CutPrefix(value, "-")
+		query.OrderBy(field, desc)
+	}
+
+	if c.Query("offset") != "" || c.Query("limit") != "" {
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil {
+			return invalid("offset", err)
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(model.Max%[2]sPageSize)))
+		if err != nil {
+			return invalid("limit", err)
+		}
+		query.Paginate(offset, limit)
+	}
+
+	return query, true
+}
+`, domainName, structName, parse.String()), stdImports, thirdPartyImports
+}
+
+// swagQueryType returns the swaggo type of a query parameter parsed to goType
+func swagQueryType(goType string) string {
+	switch goType {
+	case "bool":
+		return "boolean"
+	case "int", "int64", "uint":
+		return "int"
+	case "float64":
+		return "number"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// relationSpec is an association declared with --belongs-to or --has-many
+type relationSpec struct {
+	Domain    string // related domain, e.g. "organization"
+	HasMany   bool
+	Field     string // association field, e.g. Organization or Posts
+	Struct    string // related model struct
+	Qualifier string // package qualifier of the related model
+	Import    string // import line of the related model package
+}
+
+// parseRelations resolves the related domains of --belongs-to and --has-many.
+//
+// The related domains must exist, since the model imports their model
+// package. A relationship can only be declared from one side of a pair of
+// domains: declaring it on both would make their models import each other.
+// A has-many target carries the foreign key, so it must declare <Domain>ID.
+func parseRelations(domainName, moduleName string, belongsTo, hasMany []string) ([]relationSpec, error) {
+	var relations []relationSpec
+	seen := make(map[string]bool)
+
+	for _, kind := range []struct {
+		flag    string
+		domains []string
+	}{{"--belongs-to", belongsTo}, {"--has-many", hasMany}} {
+		for _, related := range kind.domains {
+			related = strings.TrimSpace(related)
+			if related == domainName {
+				return nil, fmt.Errorf("%s %s: a domain cannot relate to itself", kind.flag, related)
+			}
+			if seen[related] {
+				return nil, fmt.Errorf("%s %s: relationship declared twice", kind.flag, related)
+			}
+			seen[related] = true
+
+			relation := relationSpec{
+				Domain:  related,
+				HasMany: kind.flag == "--has-many",
+				Struct:  capitalize(related),
+			}
+			relation.Field = relation.Struct
+			if relation.HasMany {
+				relation.Field += "s"
+			}
+
+			// Flat domains declare their model in pkg/<domain>/<domain>.go
+			fileName := filepath.Join("pkg", related, "model", related+".go")
+			relation.Qualifier = related + "model"
+			relation.Import = fmt.Sprintf(`%s "%s/pkg/%s/model"`, relation.Qualifier, moduleName, related)
+			if _, err := os.Stat(fileName); os.IsNotExist(err) {
+				fileName = filepath.Join("pkg", related, related+".go")
+				relation.Qualifier = related
+				relation.Import = fmt.Sprintf(`"%s/pkg/%s"`, moduleName, related)
+				if _, err := os.Stat(fileName); os.IsNotExist(err) {
+					return nil, fmt.Errorf("%s %s: domain %s not found, add it first", kind.flag, related, related)
+				}
+			}
+
+			file, err := parser.ParseFile(token.NewFileSet(), fileName, nil, parser.ImportsOnly)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse model %s: %w", fileName, err)
+			}
+			ownPackage := moduleName + "/pkg/" + domainName
+			for _, spec := range file.Imports {
+				path, _ := strconv.Unquote(spec.Path.Value)
+				if path == ownPackage || strings.HasPrefix(path, ownPackage+"/") {
+					return nil, fmt.Errorf("%s %s: %s already imports pkg/%s, declaring the relationship on both sides would create an import cycle", kind.flag, related, fileName, domainName)
+				}
+			}
+
+			if relation.HasMany {
+				foreignKey := capitalize(domainName) + "ID"
+				fields, err := parseStructFields(fileName, relation.Struct)
+				if err != nil {
+					return nil, err
+				}
+				found := false
+				for _, field := range fields {
+					found = found || (field.Name == foreignKey && field.Type == "uuid.UUID")
+				}
+				if !found {
+					return nil, fmt.Errorf("%s %s: %s has no %s uuid.UUID foreign key, add it with gear add-field %s %s_id:uuid:required", kind.flag, related, relation.Struct, foreignKey, related, domainName)
+				}
+			}
+
+			relations = append(relations, relation)
+		}
+	}
+
+	return relations, nil
+}
+
+// relationFields checks fields against the associations of relations and
+// adds the foreign key of each belongs-to relation. A foreign key declared
+// in --fields is kept as declared.
+func relationFields(fields []fieldSpec, relations []relationSpec) ([]fieldSpec, error) {
+	for _, relation := range relations {
+		for _, field := range fields {
+			if strings.EqualFold(field.GoName, relation.Field) {
+				return nil, fmt.Errorf("field %s collides with the %s association", field.Name, relation.Field)
+			}
+		}
+		if relation.HasMany {
+			continue
+		}
+		foreignKey := relation.foreignKeyField()
+		declared := false
+		for i, field := range fields {
+			if strings.EqualFold(field.GoName, foreignKey.GoName) {
+				if field.GoType != "uuid.UUID" {
+					return nil, fmt.Errorf("field %s must be a uuid to reference %s", field.Name, relation.Domain)
+				}
+				fields[i].Index = true
+				declared = true
+			}
+		}
+		if !declared {
+			fields = append(fields, foreignKey)
+		}
+	}
+	return fields, nil
+}
+
+// foreignKeyField returns the <domain>_id field a belongs-to relation adds to the model
+func (r relationSpec) foreignKeyField() fieldSpec {
+	return fieldSpec{
+		Name:     r.Domain + "_id",
+		GoName:   r.Struct + "ID",
+		GoType:   "uuid.UUID",
+		Column:   "uuid",
+		Required: true,
+		Index:    true,
+	}
+}
+
+// modelRow renders the association field of the model
+func (r relationSpec) modelRow(structName string) []string {
+	if r.HasMany {
+		return []string{r.Field, "[]" + r.Qualifier + "." + r.Struct, "`gorm:\"foreignKey:" + structName + "ID\" json:\"-\"`"}
+	}
+	return []string{r.Field, "*" + r.Qualifier + "." + r.Struct, "`gorm:\"foreignKey:" + r.Struct + "ID\" json:\"-\"`"}
+}
+
+// responseRow renders the nested response field of the preloaded association
+func (r relationSpec) responseRow() []string {
+	key := r.Domain
+	if r.HasMany {
+		key += "s"
+		return []string{r.Field, "[]" + r.Qualifier + "." + r.Struct + "Response", "`json:\"" + jsonName(key) + ",omitempty\"`"}
+	}
+	return []string{r.Field, "*" + r.Qualifier + "." + r.Struct + "Response", "`json:\"" + jsonName(key) + ",omitempty\"`"}
+}
+
+// toResponseStatement renders the ToResponse() statement converting the association
+func (r relationSpec) toResponseStatement() string {
+	if r.HasMany {
+		return fmt.Sprintf(`	for i := range u.%[1]s {
+		response.%[1]s = append(response.%[1]s, *u.%[1]s[i].ToResponse())
+	}
+`, r.Field)
+	}
+	return fmt.Sprintf(`	if u.%[1]s != nil {
+		response.%[1]s = u.%[1]s.ToResponse()
+	}
+`, r.Field)
+}
+
+// preloadRelations makes GetByID and List of a rendered repository preload
+// the associations of --belongs-to and --has-many
+func preloadRelations(content string, relations []relationSpec) string {
+	var preloads strings.Builder
+	for _, relation := range relations {
+		fmt.Fprintf(&preloads, ".Preload(%q)", relation.Field)
+	}
+	for _, call := range []string{".First(", ".Find(", ".Order(", ".Scopes(", ".FindInBatches("} {
+		content = strings.ReplaceAll(content, "r.db.WithContext(ctx)"+call, "r.db.WithContext(ctx)"+preloads.String()+call)
+	}
+	return content
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Domain names that collide with the imports and keywords of the templates
+func TestAddDomainWithCollidingNames(t *testing.T) {
+	dir := t.TempDir()
+	runGear(t, dir, "init", "demo", "-m", "example.com/demo")
+	root := filepath.Join(dir, "demo")
+	for _, domain := range []string{"model", "type", "handler"} {
+		runGear(t, root, "add-domain", domain)
+	}
+
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(filepath.Join(root, "pkg"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Errorf("generated file does not parse: %v", err)
+			return nil
+		}
+		for _, shadowed := range shadowedImports(file) {
+			t.Errorf("%s: %s shadows the %[2]s import", fset.Position(shadowed.Pos()), shadowed.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, domain := range []string{"model", "type", "handler"} {
+		handler := filepath.Join(root, "pkg", domain, "handler", domain+"_handler.go")
+		if _, err := os.Stat(handler); err != nil {
+			t.Errorf("no handler generated for %s: %v", domain, err)
+		}
+	}
+
+	if testing.Short() {
+		return
+	}
+	vetProject(t, root)
+}
+
+// shadowedImports returns the identifiers declared in file under the name of
+// one of its imports
+func shadowedImports(file *ast.File) []*ast.Ident {
+	imported := make(map[string]bool)
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imported[name] = true
+	}
+
+	var shadowed []*ast.Ident
+	declare := func(idents ...*ast.Ident) {
+		for _, ident := range idents {
+			if ident != nil && imported[ident.Name] {
+				shadowed = append(shadowed, ident)
+			}
+		}
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, expr := range node.Lhs {
+					ident, _ := expr.(*ast.Ident)
+					declare(ident)
+				}
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.DEFINE {
+				key, _ := node.Key.(*ast.Ident)
+				value, _ := node.Value.(*ast.Ident)
+				declare(key, value)
+			}
+		case *ast.ValueSpec:
+			declare(node.Names...)
+		case *ast.FuncType:
+			for _, list := range []*ast.FieldList{node.Params, node.Results} {
+				if list == nil {
+					continue
+				}
+				for _, field := range list.List {
+					declare(field.Names...)
+				}
+			}
+		case *ast.FuncDecl:
+			if node.Recv != nil {
+				for _, field := range node.Recv.List {
+					declare(field.Names...)
+				}
+			}
+		}
+		return true
+	})
+	return shadowed
+}
+
+// vetProject runs go vet on a generated project, resolving its dependencies
+// from the module cache first; it skips when they are not available
+func vetProject(t *testing.T, root string) {
+	t.Helper()
+	goEnv, err := exec.Command("go", "env", "GOMODCACHE", "GOPROXY").Output()
+	if err != nil {
+		t.Skipf("go env: %v", err)
+	}
+	cache, proxy, _ := strings.Cut(strings.TrimSpace(string(goEnv)), "\n")
+	env := append(os.Environ(),
+		"GOFLAGS=-mod=mod",
+		"GOPROXY=file://"+filepath.ToSlash(filepath.Join(cache, "cache", "download"))+","+proxy,
+	)
+
+	get := exec.Command("go", "get", "github.com/google/uuid@v1.6.0")
+	get.Dir, get.Env = root, env
+	if output, err := get.CombinedOutput(); err != nil {
+		t.Skipf("dependencies of the generated project are not available: %v\n%s", err, output)
+	}
+
+	vet := exec.Command("go", "vet", "./...")
+	vet.Dir, vet.Env = root, env
+	if output, err := vet.CombinedOutput(); err != nil {
+		t.Fatalf("go vet: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// timestampTypes maps the --timestamp-format values to the internal/timestamp
+// type encoding the response timestamps, "" for time.Time itself
+var timestampTypes = map[string]string{
+	"rfc3339nano":  "",
+	"rfc3339":      "timestamp.RFC3339",
+	"epoch-millis": "timestamp.EpochMillis",
+}
+
+// generateTimestampPackage writes internal/timestamp, the types through
+// which responses encode their timestamps in one format
+func generateTimestampPackage() error {
+	content := `package timestamp
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// RFC3339 is a time encoded in JSON as RFC 3339 in UTC to the second,
+// "2024-05-01T12:30:00Z", where time.Time adds as many fractional digits
+// as it holds and so varies from one value to the next. Convert with
+// RFC3339(t) and time.Time(t).
+type RFC3339 time.Time
+
+// MarshalJSON encodes t in UTC without fractional seconds
+func (t RFC3339) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Time(t).UTC().Format(time.RFC3339))
+}
+
+// UnmarshalJSON decodes an RFC 3339 string, fractional seconds or not
+func (t *RFC3339) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return err
+	}
+	*t = RFC3339(parsed)
+	return nil
+}
+
+// EpochMillis is a time encoded in JSON as the number of milliseconds
+// since the Unix epoch, 1714566600000. Convert with EpochMillis(t) and
+// time.Time(t).
+type EpochMillis time.Time
+
+// MarshalJSON encodes t as an integer number of milliseconds
+func (t EpochMillis) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, time.Time(t).UnixMilli(), 10), nil
+}
+
+// UnmarshalJSON decodes a number of milliseconds since the Unix epoch
+func (t *EpochMillis) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	millis, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*t = EpochMillis(time.UnixMilli(millis).UTC())
+	return nil
+}
+`
+
+	return writeFile(filepath.Join("internal", "timestamp", "timestamp.go"), content)
+}
+
+// timestampRows returns the CreatedAt and UpdatedAt rows of the model, the
+// response and ToResponse. With --timestamp-format the response wraps them
+// in a type of internal/timestamp encoding them in that format.
+func timestampRows() (modelRows, responseRows, toResponseRows [][]string) {
+	modelRows = [][]string{
+		{"CreatedAt", "time.Time", "`json:\"-\"`"},
+		{"UpdatedAt", "time.Time", "`json:\"-\"`"},
+	}
+
+	timeType, timeValue, swagTag := "time.Time", "u.%s.UTC(),", ""
+	if timestampType := timestampTypes[timestampFormat]; timestampType != "" {
+		timeType, timeValue = timestampType, timestampType+"(u.%s.UTC()),"
+		if withSwag {
+			swagTag = ` swaggertype:"primitive,string" format:"date-time"`
+			if timestampFormat == "epoch-millis" {
+				swagTag = ` swaggertype:"primitive,integer"`
+			}
+		}
+	}
+	responseRows = [][]string{
+		{"CreatedAt", timeType, "`json:\"" + jsonName("created_at") + "\"" + swagTag + "`"},
+		{"UpdatedAt", timeType, "`json:\"" + jsonName("updated_at") + "\"" + swagTag + "`"},
+	}
+	toResponseRows = [][]string{
+		{"CreatedAt:", fmt.Sprintf(timeValue, "CreatedAt")},
+		{"UpdatedAt:", fmt.Sprintf(timeValue, "UpdatedAt")},
+	}
+	return modelRows, responseRows, toResponseRows
+}
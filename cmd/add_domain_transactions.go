@@ -0,0 +1,16 @@
+package cmd
+
+import "strings"
+
+// transactionImport is the import of internal/transaction, generated by
+// init --tx-middleware
+func transactionImport(moduleName string) string {
+	return `"` + moduleName + `/internal/transaction"`
+}
+
+// contextTransactionQueries makes the queries of a rendered repository run
+// in the transaction the middleware carries in the request context, or on
+// the repository's database outside of one
+func contextTransactionQueries(content string) string {
+	return strings.ReplaceAll(content, "r.db.WithContext(ctx)", "transaction.DB(ctx, r.db)")
+}
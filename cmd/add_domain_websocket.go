@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// addWebSocketDomain generates a message-driven domain served over a
+// WebSocket connection hub instead of REST CRUD endpoints
+func addWebSocketDomain(domainName, moduleName string) error {
+	if framework := detectHandler(); framework != "gin" {
+		return fmt.Errorf("--transport websocket is only supported for gin projects (detected %s)", framework)
+	}
+
+	if err := generateMessageModel(domainName); err != nil {
+		return err
+	}
+
+	if err := generateMessageService(domainName, moduleName); err != nil {
+		return err
+	}
+
+	if err := generateWebSocketHandler(domainName, moduleName); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Domain %s added successfully!\n", domainName)
+	fmt.Printf("\nGenerated files:\n")
+	fmt.Printf("  pkg/%s/model/%s.go\n", domainName, domainName)
+	fmt.Printf("  pkg/%s/service/%s_service.go\n", domainName, domainName)
+	fmt.Printf("  pkg/%s/handler/%s_handler.go\n", domainName, domainName)
+
+	return nil
+}
+
+func generateMessageModel(domainName string) error {
+	structName := capitalize(domainName)
+
+	content := fmt.Sprintf(`package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// %[2]sMessage is a message exchanged over a %[1]s WebSocket connection
+type %[2]sMessage struct {
+	Type     string          `+"`json:\"type\"`"+`
+	Payload  json.RawMessage `+"`json:\"payload,omitempty\"`"+`
+	SenderID string          `+"`json:\"%[3]s,omitempty\"`"+`
+	SentAt   time.Time       `+"`json:\"%[4]s\"`"+`
+}
+`, domainName, structName, jsonName("sender_id"), jsonName("sent_at"))
+
+	fileName := filepath.Join("pkg", domainName, "model", domainName+".go")
+	return writeFile(fileName, content)
+}
+
+func generateMessageService(domainName, moduleName string) error {
+	structName := capitalize(domainName)
+
+	content := fmt.Sprintf(`package service
+
+import (
+	"context"
+	"time"
+
+	"%[1]s/internal/errors"
+	"%[1]s/pkg/%[2]s/model"
+)
+
+// %[3]sService defines the interface for %[2]s message handling
+type %[3]sService interface {
+	// HandleMessage processes a message received from a client and returns
+	// the message to broadcast to every connected client, or nil for none
+	HandleMessage(ctx context.Context, clientID string, message model.%[3]sMessage) (*model.%[3]sMessage, error)
+}
+
+type %[2]sService struct{}
+
+// New%[3]sService creates a new %[2]s service instance
+func New%[3]sService() %[3]sService {
+	return &%[2]sService{}
+}
+
+func (s *%[2]sService) HandleMessage(ctx context.Context, clientID string, message model.%[3]sMessage) (*model.%[3]sMessage, error) {
+	if message.Type == "" {
+		return nil, errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": "type",
+		})
+	}
+
+	// TODO: Apply %[2]s business rules here; by default every message is
+	// broadcast as received
+	message.SenderID = clientID
+	message.SentAt = time.Now().UTC()
+	return &message, nil
+}
+`, moduleName, domainName, structName)
+
+	fileName := filepath.Join("pkg", domainName, "service", domainName+"_service.go")
+	return writeFile(fileName, content)
+}
+
+func generateWebSocketHandler(domainName, moduleName string) error {
+	structName := capitalize(domainName)
+
+	content := fmt.Sprintf(`package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"%[1]s/pkg/%[2]s/model"
+	"%[1]s/pkg/%[2]s/service"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 64 * 1024
+	sendBufferSize = 256
+)
+
+// %[3]sHandler handles WebSocket connections for %[2]s messages
+type %[3]sHandler interface {
+	Connect(c *gin.Context)
+	RegisterRoutes(router gin.IRouter)
+}
+
+type %[2]sHandler struct {
+	%[2]sService service.%[3]sService
+	hub         *%[2]sHub
+	upgrader    websocket.Upgrader
+}
+
+// New%[3]sHandler creates a new %[2]s handler and starts its connection hub
+func New%[3]sHandler(%[2]sService service.%[3]sService) %[3]sHandler {
+	hub := new%[3]sHub()
+	go hub.run()
+
+	return &%[2]sHandler{
+		%[2]sService: %[2]sService,
+		hub:         hub,
+		// The default upgrader rejects cross-origin requests; set CheckOrigin
+		// to allow the origins your clients are served from
+		upgrader: websocket.Upgrader{},
+	}
+}
+
+// RegisterRoutes registers all %[2]s routes
+func (h *%[2]sHandler) RegisterRoutes(router gin.IRouter) {
+	router.GET("/%[2]ss/ws", h.Connect)
+}
+
+// Connect handles GET /%[2]ss/ws requests by upgrading them to a WebSocket
+func (h *%[2]sHandler) Connect(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written the error response
+		return
+	}
+
+	client := &%[2]sClient{
+		id:   uuid.NewString(),
+		conn: conn,
+		send: make(chan []byte, sendBufferSize),
+	}
+	h.hub.register <- client
+
+	go client.writePump()
+	h.readPump(c, client)
+}
+
+// readPump feeds inbound messages to the service and broadcasts its replies
+// until the connection fails or is closed by the client
+func (h *%[2]sHandler) readPump(c *gin.Context, client *%[2]sClient) {
+	defer func() {
+		h.hub.unregister <- client
+		client.conn.Close()
+	}()
+
+	client.conn.SetReadLimit(maxMessageSize)
+	client.conn.SetReadDeadline(time.Now().Add(pongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var message model.%[3]sMessage
+		if err := client.conn.ReadJSON(&message); err != nil {
+			return
+		}
+
+		reply, err := h.%[2]sService.HandleMessage(c.Request.Context(), client.id, message)
+		if err != nil {
+			// Errors go back to the sender only, through the hub which owns
+			// the send channels
+			if data, marshalErr := json.Marshal(err); marshalErr == nil {
+				h.hub.direct <- %[2]sDelivery{client: client, data: data}
+			}
+			continue
+		}
+		if reply == nil {
+			continue
+		}
+
+		data, err := json.Marshal(reply)
+		if err != nil {
+			continue
+		}
+		h.hub.broadcast <- data
+	}
+}
+
+// %[2]sClient is a single WebSocket connection registered in the hub
+type %[2]sClient struct {
+	id   string
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// writePump writes queued messages to the connection and keeps it alive
+// with pings; it stops when the hub closes the send channel
+func (cl *%[2]sClient) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		cl.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-cl.send:
+			cl.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				cl.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := cl.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			cl.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := cl.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// %[2]sDelivery is a message addressed to a single client
+type %[2]sDelivery struct {
+	client *%[2]sClient
+	data   []byte
+}
+
+// %[2]sHub is the registry of connected clients; all its state is owned
+// by the run goroutine
+type %[2]sHub struct {
+	clients    map[*%[2]sClient]bool
+	register   chan *%[2]sClient
+	unregister chan *%[2]sClient
+	broadcast  chan []byte
+	direct     chan %[2]sDelivery
+}
+
+func new%[3]sHub() *%[2]sHub {
+	return &%[2]sHub{
+		clients:    make(map[*%[2]sClient]bool),
+		register:   make(chan *%[2]sClient),
+		unregister: make(chan *%[2]sClient),
+		broadcast:  make(chan []byte),
+		direct:     make(chan %[2]sDelivery),
+	}
+}
+
+// run serializes client registration and fans broadcasts out to clients,
+// dropping clients whose send buffer is full
+func (hub *%[2]sHub) run() {
+	for {
+		select {
+		case client := <-hub.register:
+			hub.clients[client] = true
+		case client := <-hub.unregister:
+			if hub.clients[client] {
+				delete(hub.clients, client)
+				close(client.send)
+			}
+		case delivery := <-hub.direct:
+			if hub.clients[delivery.client] {
+				select {
+				case delivery.client.send <- delivery.data:
+				default:
+				}
+			}
+		case data := <-hub.broadcast:
+			for client := range hub.clients {
+				select {
+				case client.send <- data:
+				default:
+					delete(hub.clients, client)
+					close(client.send)
+				}
+			}
+		}
+	}
+}
+`, moduleName, domainName, structName)
+
+	fileName := filepath.Join("pkg", domainName, "handler", domainName+"_handler.go")
+	return writeFile(fileName, content)
+}
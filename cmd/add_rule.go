@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// customRulesDir holds the project's own rules, checked by arch_test.go
+const customRulesDir = "internal/gearrules"
+
+var addRuleCmd = &cobra.Command{
+	Use:   "add-rule [RuleName]",
+	Short: "Add a custom validation rule to the GEAR project",
+	Long: `Add a validation rule of the project's own to internal/gearrules.
+
+The rule is a lint.ValidationRule (github.com/gomessguii/gear/pkg/lint)
+with a Check function skeleton, registered in gearrules.Rules(). Custom
+rules are numbered C01, C02... in the order they are added. The skeleton
+inspects the AST of each package with a sample check, reporting fmt.Print
+calls in the service, repository and handler layers, and comes with a test
+running it on a sample project; replace both with the rule's own logic.
+
+Custom rules run with the GEAR rules in the test of gear gen-arch-test,
+which passes gearrules.Rules() to lint.Validate. Like the GEAR rules they
+take a severity from .gearrc by ID, and "off" disables them.
+
+Examples:
+  gear add-rule NoGlobalState
+  gear gen-arch-test`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return addRule(args[0])
+	},
+}
+
+func addRule(ruleName string) error {
+	fmt.Printf("📏 Adding rule: %s\n", ruleName)
+
+	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
+		return fmt.Errorf("not in a Go project directory (go.mod not found)")
+	}
+
+	if !token.IsIdentifier(ruleName) || !token.IsExported(ruleName) {
+		return fmt.Errorf("rule name %q is not an exported Go name, e.g. NoGlobalState", ruleName)
+	}
+
+	fileName := filepath.Join(customRulesDir, toSnakeCase(ruleName)+".go")
+	if _, err := os.Stat(fileName); err == nil {
+		return fmt.Errorf("rule %s already exists", fileName)
+	}
+
+	registry := filepath.Join(customRulesDir, "rules.go")
+	registered, err := registeredRules(registry)
+	if err != nil {
+		return err
+	}
+	for _, name := range registered {
+		if name == ruleName {
+			return fmt.Errorf("rule %s is already registered in %s", ruleName, registry)
+		}
+	}
+	ruleID := fmt.Sprintf("C%02d-%s", len(registered)+1, strings.ReplaceAll(toSnakeCase(ruleName), "_", "-"))
+
+	if err := writeFile(fileName, ruleSource(ruleName, ruleID)); err != nil {
+		return err
+	}
+	testFileName := filepath.Join(customRulesDir, toSnakeCase(ruleName)+"_test.go")
+	if err := writeFile(testFileName, ruleTestSource(ruleName, ruleID)); err != nil {
+		return err
+	}
+	if err := registerRule(registry, ruleName); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Rule %s added successfully!\n", ruleID)
+	fmt.Printf("\nGenerated files:\n")
+	fmt.Printf("  %s\n", fileName)
+	fmt.Printf("  %s\n", testFileName)
+	fmt.Printf("  %s (registers %s)\n", registry, ruleName)
+	fmt.Printf("\nRun the project's rules with go test:\n")
+	fmt.Printf("  go get github.com/gomessguii/gear\n")
+	fmt.Printf("  gear gen-arch-test\n")
+	fmt.Printf("  go test ./...\n")
+
+	return nil
+}
+
+// registeredRules returns the rules listed by Rules() in the registry, none
+// when it does not exist yet
+func registeredRules(registry string) ([]string, error) {
+	list, _, err := parseRuleRegistry(registry)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, elt := range list.Elts {
+		if ident, ok := elt.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	}
+	return names, nil
+}
+
+// registerRule appends a rule to the list Rules() returns, creating the
+// registry with the first rule
+func registerRule(registry, ruleName string) error {
+	list, fset, err := parseRuleRegistry(registry)
+	if os.IsNotExist(err) {
+		return writeFile(registry, ruleRegistrySource(ruleName))
+	}
+	if err != nil {
+		return err
+	}
+
+	src, err := os.ReadFile(registry)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", registry, err)
+	}
+
+	// Insert the rule on its own line before the closing brace
+	end := fset.Position(list.Rbrace).Offset
+	lineStart := strings.LastIndex(string(src[:end]), "\n") + 1
+	entry := "\t\t" + ruleName + ",\n"
+	if strings.TrimSpace(string(src[lineStart:end])) != "" {
+		lineStart, entry = end, ",\n\t\t"+ruleName+",\n\t"
+	}
+	content := string(src[:lineStart]) + entry + string(src[lineStart:])
+	return writeFile(registry, content)
+}
+
+// parseRuleRegistry returns the composite literal Rules() returns
+func parseRuleRegistry(registry string) (*ast.CompositeLit, *token.FileSet, error) {
+	src, err := os.ReadFile(registry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, registry, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", registry, err)
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "Rules" || fn.Body == nil {
+			continue
+		}
+		for _, stmt := range fn.Body.List {
+			if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+				if list, ok := ret.Results[0].(*ast.CompositeLit); ok {
+					return list, fset, nil
+				}
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("%s has no Rules() returning a []lint.ValidationRule literal to register the rule in", registry)
+}
+
+func ruleRegistrySource(ruleName string) string {
+	return fmt.Sprintf(`// Package gearrules holds the project's own validation rules, checked with
+// the GEAR rules by arch_test.go. gear add-rule adds new ones.
+package gearrules
+
+import "github.com/gomessguii/gear/pkg/lint"
+
+// Rules lists the project's rules in ID order
+func Rules() []lint.ValidationRule {
+	return []lint.ValidationRule{
+		%[1]s,
+	}
+}
+`, ruleName)
+}
+
+func ruleSource(ruleName, ruleID string) string {
+	return fmt.Sprintf(`package gearrules
+
+import (
+	"go/ast"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+// %[1]s is the %[3]s rule. TODO: describe what it checks and why.
+var %[1]s = lint.ValidationRule{
+	Name:        %[2]q,
+	Description: "%[4]s: TODO describe the rule",
+	Check:       check%[1]s,
+	// Findings in a file only depend on that file
+	FileLocal: true,
+}
+
+// check%[1]s runs on each package of the project. The sample
+// check reports fmt.Print calls in the service, repository and handler
+// layers; replace it with the rule's own inspection.
+func check%[1]s(p *lint.Project, pkg *ast.Package) []lint.ValidationError {
+	var findings []lint.ValidationError
+
+	for filePath, file := range pkg.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			receiver, ok := sel.X.(*ast.Ident)
+			if !ok || receiver.Name != "fmt" {
+				return true
+			}
+			switch sel.Sel.Name {
+			case "Print", "Printf", "Println":
+			default:
+				return true
+			}
+
+			// LayerAt reads the layer from the directory, or from the
+			// "// gear:section" markers of flat domains
+			switch lint.LayerAt(filePath, file, call.Pos()) {
+			case "service", "repository", "handler":
+			default:
+				return true
+			}
+
+			pos := p.Fset.Position(call.Pos())
+			findings = append(findings, lint.ValidationError{
+				Rule:     %[2]q,
+				File:     filePath,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Message:  "fmt." + sel.Sel.Name + " writes to stdout - log through the request's logger instead",
+				Severity: "warning",
+			})
+			return true
+		})
+	}
+
+	return findings
+}
+`, ruleName, ruleID, ruleID[:3], splitWords(ruleName))
+}
+
+func ruleTestSource(ruleName, ruleID string) string {
+	return fmt.Sprintf(`package gearrules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+func Test%[1]s(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantLines []int
+	}{
+		{
+			name: "reported",
+			source: `+"`"+`package service
+
+import "fmt"
+
+func Greet(name string) {
+	fmt.Println("hello", name)
+}
+`+"`"+`,
+			wantLines: []int{6},
+		},
+		{
+			name: "clean",
+			source: `+"`"+`package service
+
+import "fmt"
+
+func Greet(name string) string {
+	return fmt.Sprintf("hello %%s", name)
+}
+`+"`"+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			fileName := filepath.Join(root, "pkg", "user", "service", "user_service.go")
+			if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(fileName, []byte(tt.source), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			project, err := lint.ParseProject(root, lint.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings := lint.RunRules([]lint.ValidationRule{%[1]s}, project, lint.Options{})
+
+			if len(findings) != len(tt.wantLines) {
+				t.Fatalf("got %%d findings, want %%d: %%v", len(findings), len(tt.wantLines), findings)
+			}
+			for i, finding := range findings {
+				if finding.Rule != %[2]q || finding.Line != tt.wantLines[i] {
+					t.Errorf("finding %%d = %%s at line %%d, want %%s at line %%d", i, finding.Rule, finding.Line, %[2]q, tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
+`, ruleName, ruleID)
+}
+
+// splitWords turns a Go name into words, e.g. "No global state" for NoGlobalState
+func splitWords(name string) string {
+	words := strings.ReplaceAll(toSnakeCase(name), "_", " ")
+	return capitalize(words)
+}
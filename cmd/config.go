@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/gomessguii/gear/pkg/lint"
 )
 
 var configCmd = &cobra.Command{
@@ -14,10 +16,12 @@ var configCmd = &cobra.Command{
 
 The .gearrc file allows you to customize GEAR validation behavior:
 - Set exclude patterns for files and directories
-- Configure rule severities (error, warning, info)
+- Configure rule severities (error, warning, info, or off to disable a rule)
 - Persist settings across validation runs
 
 Example .gearrc content:
+  version: 1
+  
   exclude:
     - "vendor"
     - "*_test.go" 
@@ -48,7 +52,9 @@ func generateStandaloneGearRC() error {
 		}
 	}
 
-	content := `exclude:
+	content := fmt.Sprintf(`version: %d
+
+exclude:
   - "vendor"
   - "*_test.go"
   - "*.pb.go"
@@ -62,7 +68,24 @@ rules:
   R04: "info"     # Domain boundaries (clean layer separation)
   R05: "error"    # Centralized configuration (internal/config package)
   R06: "error"    # Systematic error handling (internal/errors package)
-`
+  R07: "warning"  # Error mapping (not-found errors are not wrapped as internal)
+  R08: "warning"  # Domain independence (no import cycles between domains)
+  R09: "warning"  # Early return (handlers return after writing an error response)
+  R10: "off"      # Interface placement (opt-in: interfaces declared next to their only implementation)
+  R11: "warning"  # Model leakage (handlers serialize responses, not models)
+  R12: "info"     # File naming (layer files are named after their primary type)
+  R13: "warning"  # Bind errors (request binding errors are checked before use)
+  R14: "warning"  # Repository interface (repositories are exposed through an exported interface)
+  R15: "off"      # Receiver names (opt-in: methods of a type use the same receiver name)
+  R16: "warning"  # No panics (services, repositories and handlers return errors instead of crashing)
+  R17: "warning"  # Context propagation (handlers and services pass the request context down)
+  R18: "warning"  # Single service (handlers depend on the service of one domain)
+  R19: "warning"  # Layer direction (layers do not import the layers above them)
+  R20: "warning"  # Rows close (repositories defer closing the rows of their queries)
+  R21: "off"      # Hardcoded secrets (opt-in: no credentials in string literals)
+  R22: "off"      # Doc comments (opt-in: exported declarations of the domains are documented)
+  R23: "off"      # Body decoding (opt-in: handlers decode JSON bodies rejecting an empty one)
+`, lint.ConfigVersion)
 
 	if err := writeFile(".gearrc", content); err != nil {
 		return fmt.Errorf("failed to create .gearrc: %w", err)
@@ -71,7 +94,7 @@ rules:
 	fmt.Println("✅ .gearrc configuration file created successfully!")
 	fmt.Println("\nYou can now:")
 	fmt.Println("  - Customize exclude patterns")
-	fmt.Println("  - Adjust rule severities (error/warning/info)")
+	fmt.Println("  - Adjust rule severities (error/warning/info/off)")
 	fmt.Println("  - Run 'gear validate' to use your settings")
 
 	return nil
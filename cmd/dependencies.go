@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// writtenFiles lists the files writeFile wrote in this run, whose imports
+// checkDependencies looks up in go.mod and go.sum
+var writtenFiles []string
+
+// checkDependencies reports the packages imported by the files written in
+// this run whose module go.mod does not require, or whose checksums go.sum
+// lacks, with the go get commands adding them. With tidy it runs go mod tidy
+// instead.
+func checkDependencies(moduleName string, tidy bool) error {
+	required, err := requiredModules()
+	if err != nil {
+		return err
+	}
+	sums := summedModules()
+
+	missing := make(map[string]bool)
+	for _, fileName := range writtenFiles {
+		if filepath.Ext(fileName) != ".go" {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), fileName, nil, parser.ImportsOnly)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", fileName, err)
+		}
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || isStandardPackage(path) || path == moduleName || strings.HasPrefix(path, moduleName+"/") {
+				continue
+			}
+			switch module := requiringModule(path, required); {
+			case module == "":
+				missing[path] = true
+			case !sums[module]:
+				missing[module] = true
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if tidy {
+		fmt.Println("\n📦 Running go mod tidy to add the dependencies of the generated code...")
+		cmd := exec.Command("go", "mod", "tidy")
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("go mod tidy failed: %w", err)
+		}
+		return nil
+	}
+
+	paths := make([]string, 0, len(missing))
+	for path := range missing {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fmt.Println("\n⚠️  The generated code imports packages go.mod or go.sum do not provide yet.")
+	fmt.Println("Add them before building:")
+	for _, path := range paths {
+		fmt.Printf("  go get %s\n", path)
+	}
+	fmt.Println("or run go mod tidy (--tidy runs it after generating).")
+	return nil
+}
+
+// requiredModules returns the module paths go.mod requires
+func requiredModules() ([]string, error) {
+	data, err := os.ReadFile("go.mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	var modules []string
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = strings.TrimSpace(line[:comment])
+		}
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inBlock:
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) == 2 {
+			modules = append(modules, fields[0])
+		}
+	}
+	return modules, nil
+}
+
+// summedModules returns the modules go.sum holds the checksum of, none when
+// there is no go.sum yet
+func summedModules() map[string]bool {
+	sums := make(map[string]bool)
+	file, err := os.Open("go.sum")
+	if err != nil {
+		return sums
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// "<module> <version>/go.mod" lines only vouch for the go.mod file
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && !strings.HasSuffix(fields[1], "/go.mod") {
+			sums[fields[0]] = true
+		}
+	}
+	return sums
+}
+
+// requiringModule returns the longest required module path providing the
+// package, "" when no required module does
+func requiringModule(path string, required []string) string {
+	var module string
+	for _, candidate := range required {
+		if (path == candidate || strings.HasPrefix(path, candidate+"/")) && len(candidate) > len(module) {
+			module = candidate
+		}
+	}
+	return module
+}
+
+// isStandardPackage reports whether path is in the standard library, whose
+// first path element has no dot
+func isStandardPackage(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
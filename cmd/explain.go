@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [rule-id]",
+	Short: "Explain what a validation rule checks and why",
+	Long: `Print the documentation of a GEAR validation rule: what it checks, why it
+matters, a compliant example and a link to the full reference.
+
+Without arguments, list every rule with its default severity.
+
+Examples:
+  gear explain         # List all rules
+  gear explain R07     # Explain the error mapping rule`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			listRules()
+			return nil
+		}
+		return explainRule(args[0])
+	},
+}
+
+func listRules() {
+	for _, rule := range lint.RuleDocs() {
+		fmt.Printf("%s  %-28s [default: %s]\n", rule.ID, rule.Name, rule.DefaultSeverity)
+	}
+	fmt.Println("\nRun 'gear explain <rule-id>' for details.")
+}
+
+func explainRule(id string) error {
+	rule, ok := lint.LookupRule(id)
+	if !ok {
+		return fmt.Errorf("unknown rule %q (run 'gear explain' to list rules)", id)
+	}
+
+	fmt.Printf("📘 %s: %s [default: %s]\n\n", rule.ID, rule.Name, rule.DefaultSeverity)
+	fmt.Printf("%s\n\n", rule.Summary)
+	fmt.Printf("Why:\n%s\n\n", indent(rule.Rationale))
+	fmt.Printf("Example:\n%s\n\n", indent(rule.Example))
+	fmt.Printf("More: %s\n", rule.DocURL())
+
+	return nil
+}
+
+func indent(text string) string {
+	return "  " + strings.ReplaceAll(text, "\n", "\n  ")
+}
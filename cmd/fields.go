@@ -0,0 +1,486 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultFields is used when add-domain is called without --fields
+const defaultFields = "name:string:required"
+
+// fieldSpec is a model field parsed from --fields, e.g. "age:int:min=0,max=120"
+type fieldSpec struct {
+	Name     string // snake_case name used for JSON keys and error variables
+	GoName   string
+	GoType   string
+	Column   string // gorm column type, empty for the gorm default
+	Size     int
+	Required bool
+	Unique   bool
+	Index    bool // plain index, set on the foreign keys of --belongs-to
+	Email    bool
+	Min      string
+	Max      string
+	Sanitize bool // trimmed by Sanitize() and capped at Size by Validate()
+	// Enum holds the values of an enum(a,b,c) field, whose GoType is a
+	// string type named after the model and field (UserStatus)
+	Enum []string
+}
+
+// emailPatternDecl declares the regex generated Validate methods check emails against
+const emailPatternDecl = "var emailPattern = regexp.MustCompile(`^[^@\\s]+@[^@\\s]+\\.[^@\\s]+$`)"
+
+var fieldNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// enumPattern matches the enum(a,b,c) field type
+var enumPattern = regexp.MustCompile(`^enum\((.*)\)$`)
+
+// fieldTypes maps --fields types to Go types
+var fieldTypes = map[string]string{
+	"string":  "string",
+	"text":    "string",
+	"int":     "int",
+	"int64":   "int64",
+	"uint":    "uint",
+	"float":   "float64",
+	"float64": "float64",
+	"bool":    "bool",
+	"time":    "time.Time",
+	"uuid":    "uuid.UUID",
+}
+
+// builtinFields are declared by every generated model
+var builtinFields = []string{"ID", "CreatedAt", "UpdatedAt"}
+
+// commonInitialisms are kept upper case in generated Go names
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URL": true, "UUID": true,
+}
+
+// parseFields parses a --fields specification.
+//
+// Fields are comma separated as name:type[:modifiers]. Modifiers are comma
+// separated too, so a token without a colon continues the previous field:
+// "age:int:min=0,max=120,email:string:email" declares age and email.
+func parseFields(spec string) ([]fieldSpec, error) {
+	var fields []fieldSpec
+	var modifiers [][]string
+	declared := make(map[string]string)
+	for _, name := range builtinFields {
+		declared[strings.ToLower(name)] = name
+	}
+
+	for _, token := range splitFieldTokens(spec) {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		if !strings.Contains(token, ":") {
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("invalid field %q (expected name:type[:modifiers])", token)
+			}
+			modifiers[len(modifiers)-1] = append(modifiers[len(modifiers)-1], token)
+			continue
+		}
+
+		parts := strings.SplitN(token, ":", 3)
+		name, fieldType := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if !fieldNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid field name %q", name)
+		}
+
+		goType, ok := fieldTypes[fieldType]
+		var enum []string
+		if match := enumPattern.FindStringSubmatch(fieldType); match != nil {
+			values, err := parseEnumValues(match[1])
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+			goType, ok, enum = toGoName(name), true, values
+		}
+		if !ok {
+			return nil, fmt.Errorf("field %s: unsupported type %q", name, fieldType)
+		}
+
+		field := fieldSpec{
+			Name:   toSnakeCase(name),
+			GoName: toGoName(name),
+			GoType: goType,
+			Enum:   enum,
+		}
+
+		// Names differing only in case or underscores become the same Go field
+		key := strings.ToLower(field.GoName)
+		if previous, ok := declared[key]; ok {
+			if slices.Contains(builtinFields, previous) {
+				return nil, fmt.Errorf("field %s collides with the built-in %s field of every model", name, previous)
+			}
+			return nil, fmt.Errorf("duplicate field %s (already declared as %s)", name, previous)
+		}
+		declared[key] = name
+		switch fieldType {
+		case "string":
+			field.Size = 255
+		case "text":
+			field.Column = "text"
+		case "uuid":
+			field.Column = "uuid"
+		}
+		if len(enum) > 0 {
+			width := 32
+			for _, value := range enum {
+				width = max(width, len(value))
+			}
+			field.Column = fmt.Sprintf("varchar(%d)", width)
+		}
+
+		fields = append(fields, field)
+		modifiers = append(modifiers, nil)
+		if len(parts) == 3 && parts[2] != "" {
+			modifiers[len(modifiers)-1] = append(modifiers[len(modifiers)-1], parts[2])
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields declared")
+	}
+
+	for i := range fields {
+		for _, modifier := range modifiers[i] {
+			if err := applyFieldModifier(&fields[i], strings.TrimSpace(modifier)); err != nil {
+				return nil, fmt.Errorf("field %s: %w", fields[i].Name, err)
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// splitFieldTokens splits on commas outside parentheses
+func splitFieldTokens(spec string) []string {
+	var tokens []string
+	depth, start := 0, 0
+	for i, r := range spec {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				tokens = append(tokens, spec[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(tokens, spec[start:])
+}
+
+// parseEnumValues parses the comma separated values of enum(a,b,c); each
+// value becomes a constant, so it must be usable in a Go name
+func parseEnumValues(spec string) ([]string, error) {
+	var values []string
+	for _, value := range strings.Split(spec, ",") {
+		value = strings.TrimSpace(value)
+		if !fieldNamePattern.MatchString(value) {
+			return nil, fmt.Errorf("invalid enum value %q (expected letters, digits and underscores)", value)
+		}
+		for _, previous := range values {
+			if toGoName(previous) == toGoName(value) {
+				return nil, fmt.Errorf("duplicate enum value %s", value)
+			}
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// qualifyEnums names the enum types of fields after the model, so enum
+// fields of different domains sharing a flat package do not collide
+func qualifyEnums(fields []fieldSpec, structName string) {
+	for i := range fields {
+		if len(fields[i].Enum) > 0 {
+			fields[i].GoType = structName + fields[i].GoName
+		}
+	}
+}
+
+func applyFieldModifier(field *fieldSpec, modifier string) error {
+	key, value, hasValue := strings.Cut(modifier, "=")
+
+	switch key {
+	case "required":
+		field.Required = true
+	case "unique":
+		field.Unique = true
+	case "email":
+		if field.GoType != "string" {
+			return fmt.Errorf("email only applies to string fields")
+		}
+		field.Email = true
+	case "min", "max":
+		if !hasValue {
+			return fmt.Errorf("%s needs a value (%s=N)", key, key)
+		}
+		if len(field.Enum) > 0 {
+			return fmt.Errorf("%s: not supported for enum fields", key)
+		}
+		if err := checkBound(field.GoType, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if key == "min" {
+			field.Min = value
+		} else {
+			field.Max = value
+		}
+	case "size":
+		size, err := strconv.Atoi(value)
+		if !hasValue || err != nil || size < 1 {
+			return fmt.Errorf("size needs a positive value (size=N)")
+		}
+		if field.GoType != "string" || field.Column == "text" {
+			return fmt.Errorf("size only applies to string fields")
+		}
+		field.Size = size
+	default:
+		return fmt.Errorf("unknown modifier %q", modifier)
+	}
+	return nil
+}
+
+// checkBound validates a min/max value: a range for numbers, a length for strings
+func checkBound(goType, value string) error {
+	switch goType {
+	case "int", "int64", "string":
+		_, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case "uint":
+		_, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an unsigned integer", value)
+		}
+	case "float64":
+		_, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	default:
+		return fmt.Errorf("not supported for %s fields", goType)
+	}
+	return nil
+}
+
+// gormTag builds the gorm struct tag of a field
+func (f fieldSpec) gormTag() string {
+	var parts []string
+	if f.Column != "" {
+		parts = append(parts, "type:"+f.Column)
+	}
+	if f.Size > 0 {
+		parts = append(parts, "size:"+strconv.Itoa(f.Size))
+	}
+	if f.Required {
+		parts = append(parts, "not null")
+	}
+	if f.Unique {
+		parts = append(parts, "uniqueIndex")
+	} else if f.Index {
+		parts = append(parts, "index")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`gorm:"%s" `, strings.Join(parts, ";"))
+}
+
+// validationChecks returns the Validate() checks of a field as (condition, rule) pairs
+func (f fieldSpec) validationChecks(receiver string) [][2]string {
+	value := receiver + "." + f.GoName
+	var checks [][2]string
+
+	if f.Required {
+		switch {
+		case len(f.Enum) > 0:
+			checks = append(checks, [2]string{value + ` == ""`, "required"})
+		case f.GoType == "string":
+			checks = append(checks, [2]string{value + ` == ""`, "required"})
+		case f.GoType == "uuid.UUID":
+			checks = append(checks, [2]string{value + " == uuid.Nil", "required"})
+		case f.GoType == "time.Time":
+			checks = append(checks, [2]string{value + ".IsZero()", "required"})
+		}
+	}
+
+	if len(f.Enum) > 0 {
+		checks = append(checks, [2]string{fmt.Sprintf(`%[1]s != "" && !%[1]s.Valid()`, value), "oneof=" + strings.Join(f.Enum, " ")})
+	}
+
+	if f.Email {
+		checks = append(checks, [2]string{fmt.Sprintf(`%[1]s != "" && !emailPattern.MatchString(%[1]s)`, value), "email"})
+	}
+
+	measured := value
+	if f.GoType == "string" {
+		measured = fmt.Sprintf("utf8.RuneCountInString(%s)", value)
+	}
+	if f.Min != "" {
+		checks = append(checks, [2]string{measured + " < " + f.Min, "min=" + f.Min})
+	}
+	if f.Max != "" {
+		checks = append(checks, [2]string{measured + " > " + f.Max, "max=" + f.Max})
+	}
+	if f.Sanitize && f.GoType == "string" && f.Size > 0 {
+		size := strconv.Itoa(f.Size)
+		checks = append(checks, [2]string{measured + " > " + size, "size=" + size})
+	}
+
+	return checks
+}
+
+// validateStatements renders the Validate() checks of a field, naming it by
+// its JSON key in the returned errors
+func (f fieldSpec) validateStatements(receiver, key string) string {
+	var b strings.Builder
+	for _, check := range f.validationChecks(receiver) {
+		fmt.Fprintf(&b, `	if %s {
+		return errors.ErrInvalidInstance.WithVariables(map[string]string{
+			"field": %q,
+			"rule":  %q,
+		})
+	}
+`, check[0], key, check[1])
+	}
+	return b.String()
+}
+
+// usesUTF8 reports whether the field's checks count runes
+func (f fieldSpec) usesUTF8() bool {
+	return f.GoType == "string" && (f.Min != "" || f.Max != "" || (f.Sanitize && f.Size > 0))
+}
+
+// enumDecl renders the string type, constants and Valid() of an enum field
+func (f fieldSpec) enumDecl() string {
+	var constants [][]string
+	names := make([]string, len(f.Enum))
+	for i, value := range f.Enum {
+		names[i] = f.GoType + toGoName(value)
+		constants = append(constants, []string{names[i], f.GoType, "= " + strconv.Quote(value)})
+	}
+	return fmt.Sprintf(`
+// %[1]s is a %[2]s value, stored and serialized as its string
+type %[1]s string
+
+// %[1]s values
+const (
+%[3]s)
+
+// Valid reports whether s is one of the %[1]s values
+func (s %[1]s) Valid() bool {
+	switch s {
+	case %[4]s:
+		return true
+	}
+	return false
+}
+`, f.GoType, f.Name, alignColumns(constants, "\t"), strings.Join(names, ", "))
+}
+
+// sanitizeStatement renders the Sanitize() statement of a string field
+func (f fieldSpec) sanitizeStatement(receiver string) string {
+	value := receiver + "." + f.GoName
+	return fmt.Sprintf("\t%[1]s = strings.TrimSpace(%[1]s)\n", value)
+}
+
+// patchStatement renders the statement of a <Domain>Patch Apply() setting the
+// field on target when the patch provides it
+func (f fieldSpec) patchStatement(receiver, target string) string {
+	return fmt.Sprintf(`	if %[1]s.%[3]s != nil {
+		%[2]s.%[3]s = *%[1]s.%[3]s
+		columns = append(columns, %[3]q)
+	}
+`, receiver, target, f.GoName)
+}
+
+// jsonName returns the JSON key of a snake_case name under --json-naming
+func jsonName(name string) string {
+	return jsonKey(name, jsonNaming)
+}
+
+// jsonKey converts a snake_case name to the given JSON naming convention
+func jsonKey(name, naming string) string {
+	if naming != "camelCase" {
+		return name
+	}
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = capitalize(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+// toGoName converts a field name such as "user_id" or "firstName" to an exported Go name
+func toGoName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if upper := strings.ToUpper(part); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(capitalize(part))
+	}
+	return b.String()
+}
+
+// toSnakeCase converts camelCase to snake_case, leaving snake_case untouched
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && name[i-1] != '_' && !(name[i-1] >= 'A' && name[i-1] <= 'Z') {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// alignColumns renders rows of struct fields or key/value pairs the way gofmt aligns them
+func alignColumns(rows [][]string, indent string) string {
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for i, cell := range row[:len(row)-1] {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(indent)
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(cell + strings.Repeat(" ", widths[i]-len(cell)+1))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var archFailOn string
+
+var genArchTestCmd = &cobra.Command{
+	Use:   "gen-arch-test",
+	Short: "Generate a test running the GEAR rules with go test",
+	Long: `Generate arch_test.go in the project root, a test that validates the
+project with the GEAR rule engine (github.com/gomessguii/gear/pkg/lint), so
+go test ./... fails on architecture violations like any other test.
+
+The test reads .gearrc like gear validate: its exclusions and rule
+severities apply. Findings at or above --fail-on fail the test, each
+reported as file:line:column; lower ones are logged with go test -v.
+In projects with rules of their own, added by gear add-rule, the test
+checks the rules of internal/gearrules too.
+
+The rules run at the gear version required in go.mod, which go get
+github.com/gomessguii/gear adds.
+
+Examples:
+  gear gen-arch-test                   # Fail on error-severity findings
+  gear gen-arch-test --fail-on warning # Also fail on warnings`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return generateArchTest()
+	},
+}
+
+func init() {
+	genArchTestCmd.Flags().StringVar(&archFailOn, "fail-on", "error", "Lowest severity failing the test (error|warning|info)")
+}
+
+func generateArchTest() error {
+	fmt.Println("🧪 Generating architecture test")
+
+	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
+		return fmt.Errorf("not in a Go project directory (go.mod not found)")
+	}
+
+	if _, ok := severityRanks[archFailOn]; !ok {
+		return fmt.Errorf("unsupported --fail-on %q (expected error, warning or info)", archFailOn)
+	}
+
+	packageName, err := rootTestPackage()
+	if err != nil {
+		return err
+	}
+
+	// Custom rules of gear add-rule are checked after the GEAR rules
+	var moduleImports []string
+	validate := `findings, err := lint.Validate(".", config.Options())`
+	if _, err := os.Stat(filepath.Join(customRulesDir, "rules.go")); err == nil {
+		moduleName, err := getModuleName()
+		if err != nil {
+			return fmt.Errorf("failed to read module name: %w", err)
+		}
+		moduleImports = append(moduleImports, moduleName+"/"+customRulesDir)
+		validate = `options := config.Options()
+	options.Rules = gearrules.Rules()
+	findings, err := lint.Validate(".", options)`
+	}
+
+	content := fmt.Sprintf(`package %[1]s
+
+import (
+%[3]s)
+
+// failOn is the lowest severity failing the test; lower findings are logged
+const failOn = %[2]q
+
+var severityRanks = map[string]int{"info": 1, "warning": 2, "error": 3}
+
+// TestArchitecture checks the project against the GEAR rules, with the
+// exclusions and rule severities of .gearrc, as gear validate does
+func TestArchitecture(t *testing.T) {
+	config, err := lint.LoadConfig(".")
+	if err != nil {
+		t.Fatalf("failed to load .gearrc: %%v", err)
+	}
+
+	%[4]s
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, finding := range findings {
+		if severityRanks[finding.Severity] >= severityRanks[failOn] {
+			t.Errorf("%%s:%%d:%%d: [%%s] %%s", finding.File, finding.Line, finding.Column, finding.Rule, finding.Message)
+			continue
+		}
+		t.Logf("%%s:%%d:%%d: [%%s] %%s (%%s)", finding.File, finding.Line, finding.Column, finding.Rule, finding.Message, finding.Severity)
+	}
+}
+`, packageName, archFailOn, importBlock([]string{"testing"}, []string{"github.com/gomessguii/gear/pkg/lint"}, moduleImports), validate)
+
+	if err := writeFile("arch_test.go", content); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Architecture test generated successfully!")
+	fmt.Printf("\nGenerated files:\n")
+	fmt.Printf("  arch_test.go\n")
+	fmt.Printf("\nAdd the rule engine and run it:\n")
+	fmt.Printf("  go get github.com/gomessguii/gear\n")
+	fmt.Printf("  go test ./...\n")
+
+	return nil
+}
+
+// rootTestPackage returns the package of the test in the project root: the
+// external test package of the Go package there, or arch_test without one
+func rootTestPackage() (string, error) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		return "", err
+	}
+	for _, fileName := range files {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), fileName, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", fileName, err)
+		}
+		return file.Name.Name + "_test", nil
+	}
+	return "arch_test", nil
+}
@@ -0,0 +1,669 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var clientBaseURL string
+
+var genClientCmd = &cobra.Command{
+	Use:   "gen-client [domain-name]",
+	Short: "Generate a typed HTTP client for a domain's API",
+	Long: `Generate pkg/<domain>/client/<domain>_client.go, a net/http client with one
+method per REST endpoint of the domain, such as GetUser(ctx, id) or
+CreateUser(ctx, request), returning the response DTOs of the model package.
+
+Routes are read from the gin handler's RegisterRoutes, and the request body,
+path parameters, pagination and response type of each endpoint from the
+handler method it registers, so hand-added routes are picked up too.
+Endpoints without a JSON response (e.g. a WebSocket upgrade) are skipped.
+
+Error responses are decoded back into *errors.Error with the server's code
+and variables, so errors.HTTPStatus and errors.As work on the client side.
+The base URL is passed to NewUserClient; --base-url sets DefaultBaseURL,
+used when it is empty.
+
+Examples:
+  gear gen-client user                                   # Client for the user API
+  gear gen-client user --base-url http://users.internal  # Different default server`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return generateClient(args[0])
+	},
+}
+
+func init() {
+	genClientCmd.Flags().StringVar(&clientBaseURL, "base-url", "http://localhost:8080", "Server URL the generated client uses when given an empty base URL")
+}
+
+// clientEndpoint is a handler route the generated client calls
+type clientEndpoint struct {
+	Name       string // handler method, reused as the client method
+	HTTPMethod string
+	Path       string // gin route, e.g. /users/:id
+	PathParams []clientParam
+	Paginated  bool   // reads ?after= and ?limit= through httputil.ParsePageParams
+	Body       string // request body type, "" without a body
+	Response   string // response type, "" without a body
+}
+
+// clientParam is a path parameter of an endpoint
+type clientParam struct {
+	Name string
+	Type string
+}
+
+func generateClient(domainName string) error {
+	fmt.Printf("🔌 Generating client for domain: %s\n", domainName)
+
+	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
+		return fmt.Errorf("not in a Go project directory (go.mod not found)")
+	}
+
+	moduleName, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to read module name: %w", err)
+	}
+
+	if detectHandler() == "chi" {
+		return fmt.Errorf("gen-client reads gin route registrations, so chi projects are not supported")
+	}
+
+	// Flat domains keep the handler and model in one package whose types
+	// the client qualifies with the domain name
+	structName := capitalize(domainName)
+	handlerFile := filepath.Join("pkg", domainName, "handler", domainName+"_handler.go")
+	localPackage := ""
+	if _, err := os.Stat(handlerFile); os.IsNotExist(err) {
+		handlerFile = filepath.Join("pkg", domainName, domainName+".go")
+		localPackage = domainName
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), handlerFile, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to parse handler of domain %s: %w", domainName, err)
+	}
+
+	imports := make(map[string]string) // qualifier -> import path
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = path
+	}
+	modelPrefix := "model."
+	if localPackage != "" {
+		modelPrefix = localPackage + "."
+		imports[localPackage] = moduleName + "/pkg/" + domainName
+	}
+
+	endpoints, skipped := parseClientEndpoints(file, structName, modelPrefix, localPackage)
+	for _, route := range skipped {
+		fmt.Printf("⚠️  Skipping %s: no JSON response found\n", route)
+	}
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no REST endpoints found in %s", handlerFile)
+	}
+
+	content, err := clientSource(domainName, moduleName, endpoints, imports)
+	if err != nil {
+		return err
+	}
+
+	fileName := filepath.Join("pkg", domainName, "client", domainName+"_client.go")
+	if err := writeFile(fileName, content); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Client for %s generated successfully!\n", domainName)
+	fmt.Printf("\nGenerated files:\n")
+	fmt.Printf("  %s\n", fileName)
+	fmt.Printf("\nEndpoints:\n")
+	for _, endpoint := range endpoints {
+		fmt.Printf("  %-7s %-20s -> %s\n", endpoint.HTTPMethod, endpoint.Path, endpoint.Name)
+	}
+	fmt.Printf("\nUse it from another service:\n")
+	fmt.Printf("  %sClient := client.New%sClient(\"\", nil)\n", domainName, structName)
+
+	return nil
+}
+
+// parseClientEndpoints reads the routes of the handler's RegisterRoutes and
+// infers the types of each from the handler method it registers. It also
+// returns the routes it could not find a JSON response for.
+func parseClientEndpoints(file *ast.File, structName, modelPrefix, localPackage string) ([]clientEndpoint, []string) {
+	methods := make(map[string]*ast.FuncDecl)
+	var register *ast.FuncDecl
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Body == nil {
+			continue
+		}
+		methods[fn.Name.Name] = fn
+		if fn.Name.Name == "RegisterRoutes" {
+			register = fn
+		}
+	}
+	if register == nil {
+		return nil, nil
+	}
+
+	var endpoints []clientEndpoint
+	var skipped []string
+	groups := make(map[string]string) // group variable -> path prefix
+	ast.Inspect(register.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			// userGroup := router.Group("/users")
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			call, ok := n.Rhs[0].(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Group" {
+				return true
+			}
+			name, ok := n.Lhs[0].(*ast.Ident)
+			if !ok {
+				return true
+			}
+			groups[name.Name] = routePrefix(sel.X, groups) + stringLiteral(call.Args[0])
+		case *ast.CallExpr:
+			// userGroup.GET("/:id", ..., h.GetUser)
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || len(n.Args) < 2 {
+				return true
+			}
+			switch sel.Sel.Name {
+			case "GET", "POST", "PUT", "PATCH", "DELETE":
+			default:
+				return true
+			}
+			target, ok := n.Args[len(n.Args)-1].(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			fn := methods[target.Sel.Name]
+			if fn == nil {
+				return true
+			}
+
+			endpoint := clientEndpoint{
+				Name:       target.Sel.Name,
+				HTTPMethod: sel.Sel.Name,
+				Path:       routePrefix(sel.X, groups) + stringLiteral(n.Args[0]),
+			}
+			if !inspectClientEndpoint(&endpoint, fn, structName, modelPrefix, localPackage) {
+				skipped = append(skipped, endpoint.HTTPMethod+" "+endpoint.Path)
+				return true
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+		return true
+	})
+
+	return endpoints, skipped
+}
+
+// routePrefix returns the path prefix of the router or group expression
+// routes are registered on
+func routePrefix(expr ast.Expr, groups map[string]string) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return groups[ident.Name]
+	}
+	return ""
+}
+
+// stringLiteral returns the value of a string literal, or "" for any other
+// expression
+func stringLiteral(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// inspectClientEndpoint fills in the parameters, body and response of an
+// endpoint from its handler method. It reports false when the method writes
+// neither a JSON response nor a bodiless success status.
+func inspectClientEndpoint(endpoint *clientEndpoint, fn *ast.FuncDecl, structName, modelPrefix, localPackage string) bool {
+	qualify := func(expr ast.Expr) string { return clientTypeString(expr, localPackage) }
+	responseType := "*" + modelPrefix + structName + "Response"
+
+	vars := make(map[string]string) // local variable -> client-side type
+	uuidParams := make(map[string]bool)
+	bodyVar := ""
+	success := false
+	var responseExpr ast.Expr
+
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.ValueSpec:
+			// var request model.UserRequest
+			if n.Type != nil {
+				for _, name := range n.Names {
+					vars[name.Name] = qualify(n.Type)
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			name, ok := n.Lhs[0].(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if value := clientExprType(n.Rhs[0], vars, responseType, qualify); value != "" {
+				vars[name.Name] = value
+			}
+		case *ast.CallExpr:
+			switch fun := n.Fun.(type) {
+			case *ast.IndexExpr:
+				// request := httputil.Body[model.UserRequest](c)
+				if sel, ok := fun.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Body" {
+					endpoint.Body = qualify(fun.Index)
+				}
+			case *ast.SelectorExpr:
+				switch fun.Sel.Name {
+				case "ShouldBindJSON", "BindJSON", "DecodeJSON":
+					// c.ShouldBindJSON(&request), httputil.DecodeJSON(c.Request, &request)
+					if len(n.Args) > 0 {
+						if unary, ok := n.Args[len(n.Args)-1].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+							if ident, ok := unary.X.(*ast.Ident); ok {
+								bodyVar = ident.Name
+							}
+						}
+					}
+				case "ParseUUIDParam":
+					if len(n.Args) == 2 {
+						uuidParams[stringLiteral(n.Args[1])] = true
+					}
+				case "ParsePageParams":
+					endpoint.Paginated = true
+				case "JSON":
+					if len(n.Args) == 2 && isSuccessStatus(n.Args[0]) {
+						responseExpr = n.Args[1]
+						success = true
+					}
+				case "RespondJSON":
+					// httputil.RespondJSON(c, http.StatusOK, response)
+					if len(n.Args) == 3 && isSuccessStatus(n.Args[1]) {
+						responseExpr = n.Args[2]
+						success = true
+					}
+				case "Status":
+					if len(n.Args) == 1 && isSuccessStatus(n.Args[0]) {
+						success = true
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	if bodyVar != "" {
+		endpoint.Body = vars[bodyVar]
+	}
+	if responseExpr != nil {
+		endpoint.Response = clientExprType(responseExpr, vars, responseType, qualify)
+		if endpoint.Response == "" {
+			return false
+		}
+	}
+	for _, segment := range strings.Split(endpoint.Path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			param := clientParam{Name: name, Type: "string"}
+			if uuidParams[name] {
+				param.Type = "uuid.UUID"
+			}
+			endpoint.PathParams = append(endpoint.PathParams, param)
+		}
+	}
+
+	return success
+}
+
+// clientExprType returns the client-side type of a value a handler encodes:
+// a ToResponse() result, a variable of known type, or a pagination page of
+// them. It returns "" for anything else.
+func clientExprType(expr ast.Expr, vars map[string]string, responseType string, qualify func(ast.Expr) string) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return vars[e.Name]
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "make" && len(e.Args) > 0 {
+			return qualify(e.Args[0])
+		}
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		switch sel.Sel.Name {
+		case "ToResponse":
+			return responseType
+		case "NewPage":
+			if len(e.Args) == 0 {
+				return ""
+			}
+			items := clientExprType(e.Args[0], vars, responseType, qualify)
+			if !strings.HasPrefix(items, "[]") {
+				return ""
+			}
+			return "pagination.Page[" + strings.TrimPrefix(items, "[]") + "]"
+		}
+	}
+	return ""
+}
+
+// isSuccessStatus reports whether expr is a 2xx net/http status constant
+func isSuccessStatus(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "StatusOK", "StatusCreated", "StatusAccepted", "StatusNoContent":
+		return true
+	}
+	return false
+}
+
+// clientTypeString prints a type expression of the handler file, qualifying
+// the exported types of a flat domain package with its name
+func clientTypeString(expr ast.Expr, localPackage string) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if localPackage != "" && t.IsExported() {
+			return localPackage + "." + t.Name
+		}
+		return t.Name
+	case *ast.SelectorExpr:
+		return clientTypeString(t.X, "") + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + clientTypeString(t.X, localPackage)
+	case *ast.ArrayType:
+		return "[]" + clientTypeString(t.Elt, localPackage)
+	case *ast.MapType:
+		return "map[" + clientTypeString(t.Key, localPackage) + "]" + clientTypeString(t.Value, localPackage)
+	case *ast.IndexExpr:
+		return clientTypeString(t.X, localPackage) + "[" + clientTypeString(t.Index, localPackage) + "]"
+	}
+	return "any"
+}
+
+// qualifierPattern matches the package qualifiers of a type string
+var qualifierPattern = regexp.MustCompile(`\b([a-z][a-zA-Z0-9_]*)\.`)
+
+// clientSource renders the client package of a domain
+func clientSource(domainName, moduleName string, endpoints []clientEndpoint, imports map[string]string) (string, error) {
+	structName := capitalize(domainName)
+	clientName := domainName + "Client"
+
+	stdImports := []string{"bytes", "context", "encoding/json", "fmt", "io", "net/http", "strings"}
+	var thirdPartyImports, moduleImports []string
+	addImport := func(qualifier string) error {
+		path, ok := imports[qualifier]
+		if !ok {
+			return fmt.Errorf("cannot resolve package %s used by the handler types", qualifier)
+		}
+		for _, existing := range append(append(append([]string(nil), stdImports...), thirdPartyImports...), moduleImports...) {
+			if existing == path {
+				return nil
+			}
+		}
+		switch {
+		case strings.HasPrefix(path, moduleName+"/"):
+			moduleImports = append(moduleImports, path)
+		case strings.Contains(path, "."):
+			thirdPartyImports = append(thirdPartyImports, path)
+		default:
+			stdImports = append(stdImports, path)
+		}
+		return nil
+	}
+	imports["errors"] = moduleName + "/internal/errors"
+	imports["pagination"] = moduleName + "/internal/pagination"
+	imports["uuid"] = "github.com/google/uuid"
+	if err := addImport("errors"); err != nil {
+		return "", err
+	}
+
+	var methods, signatures strings.Builder
+	paginated, escaped := false, false
+	for _, endpoint := range endpoints {
+		params := []string{"ctx context.Context"}
+		var types []string
+		for _, param := range endpoint.PathParams {
+			params = append(params, param.Name+" "+param.Type)
+			types = append(types, param.Type)
+		}
+		if endpoint.Paginated {
+			params = append(params, "after string", "limit int")
+			paginated = true
+		}
+		if endpoint.Body != "" {
+			params = append(params, "request "+endpoint.Body)
+			types = append(types, endpoint.Body)
+		}
+
+		// Value responses such as a page envelope are returned by pointer
+		result := endpoint.Response
+		returned := "response"
+		if result != "" && !strings.HasPrefix(result, "*") && !strings.HasPrefix(result, "[]") && !strings.HasPrefix(result, "map[") {
+			result = "*" + result
+			returned = "&response"
+		}
+		if endpoint.Response != "" {
+			types = append(types, endpoint.Response)
+		}
+		for _, typeString := range types {
+			for _, match := range qualifierPattern.FindAllStringSubmatch(typeString, -1) {
+				if err := addImport(match[1]); err != nil {
+					return "", err
+				}
+			}
+		}
+
+		// "/users/" + url.PathEscape(id.String())
+		var path []string
+		literal := ""
+		for i, segment := range strings.Split(endpoint.Path, "/") {
+			if i > 0 {
+				literal += "/"
+			}
+			name, ok := strings.CutPrefix(segment, ":")
+			if !ok {
+				literal += segment
+				continue
+			}
+			if literal != "" {
+				path = append(path, strconv.Quote(literal))
+				literal = ""
+			}
+			value := name
+			for _, param := range endpoint.PathParams {
+				if param.Name == name && param.Type != "string" {
+					value = name + ".String()"
+				}
+			}
+			path = append(path, "url.PathEscape("+value+")")
+			escaped = true
+		}
+		if literal != "" || len(path) == 0 {
+			path = append(path, strconv.Quote(literal))
+		}
+		if endpoint.Paginated {
+			path = append(path, "pageQuery(after, limit)")
+		}
+
+		body := "nil"
+		if endpoint.Body != "" {
+			body = "request"
+		}
+		method := "http.Method" + capitalize(strings.ToLower(endpoint.HTTPMethod))
+		signature := fmt.Sprintf("%s(%s) error", endpoint.Name, strings.Join(params, ", "))
+		if result != "" {
+			signature = fmt.Sprintf("%s(%s) (%s, error)", endpoint.Name, strings.Join(params, ", "), result)
+		}
+		fmt.Fprintf(&signatures, "\t%s\n", signature)
+
+		if result == "" {
+			fmt.Fprintf(&methods, `
+// %[1]s calls %[2]s %[3]s
+func (c *%[4]s) %[5]s {
+	return c.do(ctx, %[6]s, %[7]s, %[8]s, nil)
+}
+`, endpoint.Name, endpoint.HTTPMethod, endpoint.Path, clientName, signature, method, strings.Join(path, "+"), body)
+			continue
+		}
+		fmt.Fprintf(&methods, `
+// %[1]s calls %[2]s %[3]s
+func (c *%[4]s) %[5]s {
+	var response %[9]s
+	if err := c.do(ctx, %[6]s, %[7]s, %[8]s, &response); err != nil {
+		return nil, err
+	}
+	return %[10]s, nil
+}
+`, endpoint.Name, endpoint.HTTPMethod, endpoint.Path, clientName, signature, method, strings.Join(path, "+"), body,
+			endpoint.Response, returned)
+	}
+
+	pageQuery := ""
+	if paginated || escaped {
+		stdImports = append(stdImports, "net/url")
+	}
+	if paginated {
+		stdImports = append(stdImports, "strconv")
+		pageQuery = `
+// pageQuery encodes the cursor pagination parameters: an empty after starts
+// at the first page and a zero limit uses the server default
+func pageQuery(after string, limit int) string {
+	query := url.Values{}
+	if after != "" {
+		query.Set("after", after)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+`
+	}
+
+	content := fmt.Sprintf(`package client
+
+import (
+%[1]s)
+
+// DefaultBaseURL is the server New%[2]sClient uses when given an empty base URL
+const DefaultBaseURL = %[3]q
+
+// %[2]sClient calls the %[4]s API over HTTP
+type %[2]sClient interface {
+%[5]s}
+
+type %[6]s struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New%[2]sClient creates a client for the %[4]s API served at baseURL. A nil
+// httpClient uses http.DefaultClient; pass one with a custom Transport to add
+// authentication headers or timeouts.
+func New%[2]sClient(baseURL string, httpClient *http.Client) %[2]sClient {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &%[6]s{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+%[7]s
+// errorBody is the JSON encoding of an *errors.Error response
+type errorBody struct {
+	Code      string
+	Message   string
+	Variables map[string]string
+}
+
+// do sends a JSON request and decodes a successful response into out.
+// Error responses are returned as *errors.Error with the server's code.
+func (c *%[6]s) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.ErrInvalidInstance.WithVariables(map[string]string{
+				"field": "request body",
+			}).WithError(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return errors.ErrInternalInstance.WithError(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.ErrInternalInstance.WithError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		status := fmt.Errorf("%%s %%s: %%s", method, path, resp.Status)
+		var errBody errorBody
+		if err := json.NewDecoder(resp.Body).Decode(&errBody); err != nil || errBody.Code == "" {
+			return errors.ErrInternalInstance.WithError(status)
+		}
+		domainErr := errors.NewError(errBody.Code).WithVariables(errBody.Variables).WithError(status)
+		domainErr.Message = errBody.Message
+		return domainErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.ErrInternalInstance.WithError(fmt.Errorf("failed to decode %%s %%s response: %%w", method, path, err))
+	}
+	return nil
+}
+%[8]s`, importBlock(stdImports, thirdPartyImports, moduleImports), structName, clientBaseURL, domainName,
+		signatures.String(), clientName, methods.String(), pageQuery)
+
+	return content, nil
+}
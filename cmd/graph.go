@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+var graphFormat string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Print the package dependency graph of the current project",
+	Long: `Print the dependencies between the packages of the project as a Graphviz
+DOT or Mermaid graph. Nodes are the module's packages, grouped by domain
+and colored by layer; edges are their imports. Edges breaking the layer
+direction (a lower layer importing a higher one, as R19 reports) or
+closing an import cycle between domains (R08) are drawn in red.
+
+Test files are left out, and exclusions come from .gearrc, as with gear
+validate.
+
+Examples:
+  gear graph | dot -Tpng -o graph.png   # Render with Graphviz
+  gear graph --format mermaid           # Mermaid flowchart for Markdown docs
+  gear graph --exclude cmd              # Leave cmd/ out of the graph`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printGraph(os.Stdout)
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "Output format (dot|mermaid)")
+	graphCmd.Flags().StringSliceVarP(&excludeDirs, "exclude", "e", []string{}, "Comma-separated list of directories to exclude from the graph")
+}
+
+// layerColors are the node fill colors of the layers; other packages are gray
+var layerColors = map[string]string{
+	"model":      "#d9ead3",
+	"repository": "#fff2cc",
+	"service":    "#cfe2f3",
+	"handler":    "#d9d2e9",
+}
+
+// graphNode is a package of the module, identified by its directory
+type graphNode struct {
+	Path   string // directory relative to the project root, e.g. pkg/user/service
+	Domain string // domain under pkg/, "" for other packages
+	Layer  string // layer of the directory, "" for flat domains and other packages
+}
+
+// graphEdge is an import between two packages of the module
+type graphEdge struct {
+	From, To  string
+	Violation string // why the import breaks the architecture, "" when it does not
+}
+
+func printGraph(out io.Writer) error {
+	if graphFormat != "dot" && graphFormat != "mermaid" {
+		return fmt.Errorf("invalid --format %q (expected dot or mermaid)", graphFormat)
+	}
+	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
+		return fmt.Errorf("not in a Go project directory (go.mod not found)")
+	}
+	moduleName, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to read module name: %w", err)
+	}
+
+	config, err := lint.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load .gearrc: %w", err)
+	}
+	opts, err := applyGearConfig(config, io.Discard)
+	if err != nil {
+		return err
+	}
+	project, err := lint.ParseProject(".", opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse project: %w", err)
+	}
+
+	nodes, edges := buildGraph(project, moduleName)
+	if graphFormat == "mermaid" {
+		writeMermaidGraph(out, nodes, edges)
+	} else {
+		writeDOTGraph(out, nodes, edges)
+	}
+	return nil
+}
+
+// buildGraph collects the packages of the project and the imports between
+// them, marking the imports that break the architecture
+func buildGraph(project *lint.Project, moduleName string) ([]graphNode, []graphEdge) {
+	nodes := make(map[string]graphNode)
+	imports := make(map[[2]string]bool)
+	for _, pkg := range project.Packages {
+		for filePath, file := range pkg.Files {
+			if strings.HasSuffix(filePath, "_test.go") {
+				continue
+			}
+			dir := filepath.ToSlash(filepath.Dir(filePath))
+			nodes[dir] = packageNode(dir)
+
+			for _, spec := range file.Imports {
+				path, err := strconv.Unquote(spec.Path.Value)
+				if err != nil {
+					continue
+				}
+				target, ok := strings.CutPrefix(path, moduleName+"/")
+				if !ok || target == dir {
+					continue
+				}
+				imports[[2]string{dir, target}] = true
+			}
+		}
+	}
+
+	// Domains reachable from each domain, to find the imports closing a cycle
+	domainImports := make(map[string]map[string]bool)
+	for edge := range imports {
+		from, to := nodes[edge[0]].Domain, packageNode(edge[1]).Domain
+		if from != "" && to != "" && from != to {
+			if domainImports[from] == nil {
+				domainImports[from] = make(map[string]bool)
+			}
+			domainImports[from][to] = true
+		}
+	}
+
+	var edges []graphEdge
+	for edge := range imports {
+		// Imports of excluded packages are left out with them
+		to, ok := nodes[edge[1]]
+		if !ok {
+			continue
+		}
+		from := nodes[edge[0]]
+		edges = append(edges, graphEdge{From: from.Path, To: to.Path, Violation: importViolation(from, to, domainImports)})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+
+	sorted := make([]graphNode, 0, len(nodes))
+	for _, node := range nodes {
+		sorted = append(sorted, node)
+	}
+	// Packages outside the domains come first, then each domain's together
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Domain != sorted[j].Domain {
+			return sorted[i].Domain < sorted[j].Domain
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted, edges
+}
+
+// packageNode returns the node of the package in dir
+func packageNode(dir string) graphNode {
+	node := graphNode{Path: dir, Domain: lint.DomainOfPath(dir, "pkg/")}
+	if node.Domain != "" {
+		for _, layer := range lint.LayerNames {
+			if strings.HasSuffix(dir, "/"+layer) || strings.Contains(dir, "/"+layer+"/") {
+				node.Layer = layer
+			}
+		}
+	}
+	return node
+}
+
+// importViolation describes how an import from one package to another
+// breaks the layer direction or closes a cycle between domains
+func importViolation(from, to graphNode, domainImports map[string]map[string]bool) string {
+	fromRank, toRank := slices.Index(lint.LayerNames, from.Layer), slices.Index(lint.LayerNames, to.Layer)
+	if fromRank >= 0 && toRank > fromRank {
+		return fmt.Sprintf("%s imports %s", from.Layer, to.Layer)
+	}
+	if from.Domain != "" && to.Domain != "" && from.Domain != to.Domain && reachesDomain(to.Domain, from.Domain, domainImports) {
+		return fmt.Sprintf("cycle between %s and %s", from.Domain, to.Domain)
+	}
+	return ""
+}
+
+// reachesDomain reports whether domain from imports domain to, directly or
+// through other domains
+func reachesDomain(from, to string, domainImports map[string]map[string]bool) bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		domain := queue[0]
+		queue = queue[1:]
+		for next := range domainImports[domain] {
+			if next == to {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// writeDOTGraph prints the graph in Graphviz DOT, one cluster per domain
+func writeDOTGraph(out io.Writer, nodes []graphNode, edges []graphEdge) {
+	fmt.Fprintln(out, "digraph gear {")
+	fmt.Fprintln(out, "\trankdir=LR;")
+	fmt.Fprintln(out, "\tnode [shape=box, style=\"rounded,filled\", fillcolor=\"#eeeeee\", fontname=\"Helvetica\"];")
+
+	domain := ""
+	for _, node := range nodes {
+		if node.Domain != domain {
+			if domain != "" {
+				fmt.Fprintln(out, "\t}")
+			}
+			domain = node.Domain
+			if domain != "" {
+				fmt.Fprintf(out, "\n\tsubgraph %q {\n\t\tlabel=%q;\n", "cluster_"+domain, domain)
+			} else {
+				fmt.Fprintln(out)
+			}
+		}
+
+		indent := "\t"
+		if domain != "" {
+			indent = "\t\t"
+		}
+		attributes := fmt.Sprintf("label=%q", node.Path)
+		if color, ok := layerColors[node.Layer]; ok {
+			attributes += fmt.Sprintf(", fillcolor=%q", color)
+		}
+		fmt.Fprintf(out, "%s%q [%s];\n", indent, node.Path, attributes)
+	}
+	if domain != "" {
+		fmt.Fprintln(out, "\t}")
+	}
+
+	fmt.Fprintln(out)
+	for _, edge := range edges {
+		if edge.Violation != "" {
+			fmt.Fprintf(out, "\t%q -> %q [color=red, penwidth=2, tooltip=%q];\n", edge.From, edge.To, edge.Violation)
+			continue
+		}
+		fmt.Fprintf(out, "\t%q -> %q;\n", edge.From, edge.To)
+	}
+	fmt.Fprintln(out, "}")
+}
+
+// writeMermaidGraph prints the graph as a Mermaid flowchart, one subgraph per domain
+func writeMermaidGraph(out io.Writer, nodes []graphNode, edges []graphEdge) {
+	id := func(path string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		}, path)
+	}
+
+	fmt.Fprintln(out, "flowchart LR")
+	domain := ""
+	for _, node := range nodes {
+		if node.Domain != domain {
+			if domain != "" {
+				fmt.Fprintln(out, "    end")
+			}
+			domain = node.Domain
+			if domain != "" {
+				fmt.Fprintf(out, "    subgraph domain_%s [%s]\n", id(domain), domain)
+			}
+		}
+
+		indent := "    "
+		if domain != "" {
+			indent = "        "
+		}
+		fmt.Fprintf(out, "%s%s[\"%s\"]\n", indent, id(node.Path), node.Path)
+	}
+	if domain != "" {
+		fmt.Fprintln(out, "    end")
+	}
+
+	var violations []string
+	for i, edge := range edges {
+		fmt.Fprintf(out, "    %s --> %s\n", id(edge.From), id(edge.To))
+		if edge.Violation != "" {
+			violations = append(violations, strconv.Itoa(i))
+		}
+	}
+
+	for _, layer := range lint.LayerNames {
+		var members []string
+		for _, node := range nodes {
+			if node.Layer == layer {
+				members = append(members, id(node.Path))
+			}
+		}
+		if len(members) > 0 {
+			fmt.Fprintf(out, "    classDef %s fill:%s\n", layer, layerColors[layer])
+			fmt.Fprintf(out, "    class %s %s\n", strings.Join(members, ","), layer)
+		}
+	}
+	if len(violations) > 0 {
+		fmt.Fprintf(out, "    linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(violations, ","))
+	}
+}
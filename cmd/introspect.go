@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+	"github.com/spf13/cobra"
+)
+
+var (
+	introspectTable  string
+	introspectDomain string
+	introspectSchema string
+	databaseURL      string
+)
+
+var introspectCmd = &cobra.Command{
+	Use:   "introspect",
+	Short: "Generate a domain from an existing database table",
+	Long: `Generate a domain from an existing PostgreSQL table.
+
+The table's columns are read from information_schema and turned into model
+fields, then the model, repository, service and handler are generated as
+add-domain would. SQL types map to the --fields types:
+
+  varchar, char       string (size from the column length)
+  text                text
+  smallint, integer   int
+  bigint              int64
+  numeric, real, ...  float
+  boolean             bool
+  date, timestamp     time
+  uuid                uuid
+
+NOT NULL columns without a default become required, single-column unique
+constraints unique. The id, created_at, updated_at and deleted_at columns
+are managed by the generated model; columns of other types are skipped with
+a warning.
+
+Examples:
+  gear introspect --table users                       # pkg/user from public.users
+  gear introspect --table account_users --domain member
+  gear introspect --table users --database-url postgres://localhost/app`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return introspect()
+	},
+}
+
+func init() {
+	introspectCmd.Flags().StringVar(&introspectTable, "table", "", "Table to generate the domain from")
+	introspectCmd.Flags().StringVar(&introspectDomain, "domain", "", "Domain name (default: the table name without a trailing s)")
+	introspectCmd.Flags().StringVar(&introspectSchema, "schema", "public", "Schema of the table")
+	introspectCmd.Flags().StringVar(&databaseURL, "database-url", "", "PostgreSQL connection string (default: $DATABASE_URL)")
+	introspectCmd.MarkFlagRequired("table")
+}
+
+// tableColumn is a column read from information_schema.columns
+type tableColumn struct {
+	Name       string
+	DataType   string
+	Nullable   bool
+	HasDefault bool
+	MaxLength  sql.NullInt64
+	Unique     bool
+}
+
+// managedColumns are declared by every generated model
+var managedColumns = map[string]bool{"id": true, "created_at": true, "updated_at": true, "deleted_at": true}
+
+func introspect() error {
+	fmt.Printf("🔎 Introspecting table: %s.%s\n", introspectSchema, introspectTable)
+
+	if databaseURL == "" {
+		databaseURL = os.Getenv("DATABASE_URL")
+	}
+	if databaseURL == "" {
+		return fmt.Errorf("no database to introspect: pass --database-url or set DATABASE_URL")
+	}
+
+	domainName := introspectDomain
+	if domainName == "" {
+		domainName = strings.TrimSuffix(introspectTable, "s")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	columns, err := readTableColumns(db, introspectSchema, introspectTable)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("table %s.%s not found or has no columns", introspectSchema, introspectTable)
+	}
+
+	var specs []string
+	for _, column := range columns {
+		if managedColumns[column.Name] {
+			if column.Name == "id" && column.DataType != "uuid" {
+				fmt.Printf("⚠️  Column id is %s; generated repositories look rows up by uuid.UUID\n", column.DataType)
+			}
+			continue
+		}
+		if toSnakeCase(column.Name) != column.Name || !fieldNamePattern.MatchString(column.Name) {
+			fmt.Printf("⚠️  Skipping column %s: only snake_case column names map to model fields\n", column.Name)
+			continue
+		}
+		spec, ok := columnFieldSpec(column)
+		if !ok {
+			fmt.Printf("⚠️  Skipping column %s: unsupported type %s\n", column.Name, column.DataType)
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return fmt.Errorf("table %s.%s has no columns gear can map to model fields", introspectSchema, introspectTable)
+	}
+
+	fieldsSpec = strings.Join(specs, ",")
+	fmt.Printf("📋 Fields: %s\n", fieldsSpec)
+
+	return addDomain(domainName)
+}
+
+// readTableColumns lists the columns of a table in declaration order
+func readTableColumns(db *sql.DB, schema, table string) ([]tableColumn, error) {
+	rows, err := db.Query(`
+		SELECT c.column_name, c.data_type, c.is_nullable = 'YES', c.column_default IS NOT NULL,
+		       c.character_maximum_length,
+		       EXISTS (
+		           SELECT 1
+		           FROM information_schema.table_constraints tc
+		           JOIN information_schema.key_column_usage k
+		             ON k.constraint_schema = tc.constraint_schema AND k.constraint_name = tc.constraint_name
+		           WHERE tc.table_schema = c.table_schema AND tc.table_name = c.table_name
+		             AND tc.constraint_type = 'UNIQUE' AND k.column_name = c.column_name
+		             AND (SELECT count(*) FROM information_schema.key_column_usage k2
+		                  WHERE k2.constraint_schema = tc.constraint_schema AND k2.constraint_name = tc.constraint_name) = 1
+		       )
+		FROM information_schema.columns c
+		WHERE c.table_schema = $1 AND c.table_name = $2
+		ORDER BY c.ordinal_position`, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	var columns []tableColumn
+	for rows.Next() {
+		var column tableColumn
+		if err := rows.Scan(&column.Name, &column.DataType, &column.Nullable, &column.HasDefault, &column.MaxLength, &column.Unique); err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s.%s: %w", schema, table, err)
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// columnFieldSpec converts a column to a --fields entry, reporting false
+// for SQL types without a field type
+func columnFieldSpec(column tableColumn) (string, bool) {
+	var fieldType string
+	var modifiers []string
+
+	switch column.DataType {
+	case "character varying", "character":
+		fieldType = "string"
+		if !column.MaxLength.Valid {
+			fieldType = "text"
+		} else {
+			modifiers = append(modifiers, fmt.Sprintf("size=%d", column.MaxLength.Int64))
+		}
+	case "text":
+		fieldType = "text"
+	case "smallint", "integer":
+		fieldType = "int"
+	case "bigint":
+		fieldType = "int64"
+	case "numeric", "real", "double precision":
+		fieldType = "float"
+	case "boolean":
+		fieldType = "bool"
+	case "date", "timestamp without time zone", "timestamp with time zone":
+		fieldType = "time"
+	case "uuid":
+		fieldType = "uuid"
+	default:
+		return "", false
+	}
+
+	if !column.Nullable && !column.HasDefault {
+		modifiers = append([]string{"required"}, modifiers...)
+	}
+	if column.Unique {
+		modifiers = append(modifiers, "unique")
+	}
+
+	spec := column.Name + ":" + fieldType
+	if len(modifiers) > 0 {
+		spec += ":" + strings.Join(modifiers, ",")
+	}
+	return spec, true
+}
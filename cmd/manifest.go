@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// manifestPath is where generated file hashes are stored, relative to the
+// project root
+const manifestPath = ".gear/manifest.json"
+
+// manifestRoot is the project root generated files are recorded against;
+// init points it at the new project directory
+var manifestRoot = "."
+
+// GenerationManifest records every file gear generated so upgrades can tell
+// untouched files from files the user has edited
+type GenerationManifest struct {
+	Files map[string]GeneratedFile `json:"files"`
+}
+
+// GeneratedFile is the hash of a generated file and the gear command that
+// produced it
+type GeneratedFile struct {
+	Hash    string   `json:"hash"`
+	Command []string `json:"command"`
+}
+
+// loadManifest reads the manifest under root, returning an empty one when
+// the project has none yet
+func loadManifest(root string) (*GenerationManifest, error) {
+	manifest := &GenerationManifest{
+		Files: make(map[string]GeneratedFile),
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, manifestPath))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", manifestPath, err)
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]GeneratedFile)
+	}
+
+	return manifest, nil
+}
+
+// saveManifest writes the manifest under root
+func saveManifest(root string, manifest *GenerationManifest) error {
+	path := filepath.Join(root, manifestPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", manifestPath, err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", manifestPath, err)
+	}
+
+	return nil
+}
+
+// recordGeneratedFile stores the hash of a freshly written file together
+// with the command line that generated it
+func recordGeneratedFile(fileName, content string) error {
+	relPath, err := filepath.Rel(manifestRoot, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", fileName, err)
+	}
+
+	manifest, err := loadManifest(manifestRoot)
+	if err != nil {
+		return err
+	}
+
+	manifest.Files[filepath.ToSlash(relPath)] = GeneratedFile{
+		Hash:    hashContent(content),
+		Command: os.Args[1:],
+	}
+
+	return saveManifest(manifestRoot, manifest)
+}
+
+// hashContent returns the hex-encoded SHA-256 of content
+func hashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
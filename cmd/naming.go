@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+var (
+	// interfaceNameTemplate, structNameTemplate and constructorNameTemplate
+	// override the naming templates of .gearrc
+	interfaceNameTemplate   string
+	structNameTemplate      string
+	constructorNameTemplate string
+)
+
+// layerNames maps the layer type names of gear's templates to the names the
+// naming templates derive, for the domain being generated. Interfaces and
+// constructors are renamed wherever they appear, structs only where they are
+// used as types, since handlers hold their service in a field of that name.
+var layerNames struct {
+	types   map[string]string
+	structs map[string]string
+}
+
+// loadNaming returns the naming templates of .gearrc, overridden by the
+// --interface-name, --struct-name and --constructor-name flags
+func loadNaming() (lint.Naming, error) {
+	config, err := lint.LoadConfig(".")
+	if err != nil {
+		return lint.Naming{}, err
+	}
+
+	naming := config.Naming
+	for _, override := range []struct {
+		template *string
+		value    string
+	}{
+		{&naming.Interface, interfaceNameTemplate},
+		{&naming.Struct, structNameTemplate},
+		{&naming.Constructor, constructorNameTemplate},
+	} {
+		if override.value != "" {
+			*override.template = override.value
+		}
+	}
+
+	if err := naming.Validate(); err != nil {
+		return lint.Naming{}, err
+	}
+	return naming, nil
+}
+
+// setLayerNames prepares the renames of a domain's layer types; the default
+// naming renames nothing. Flat domains declare every layer in one package,
+// so their names must all differ.
+func setLayerNames(naming lint.Naming, domainName string) error {
+	layerNames.types, layerNames.structs = nil, nil
+	if naming.IsDefault() {
+		return nil
+	}
+
+	layerNames.types = make(map[string]string)
+	layerNames.structs = make(map[string]string)
+	declared := make(map[string]string)
+	for _, layer := range lint.NamingLayers {
+		suffix := capitalize(layer)
+		renames := []struct {
+			names    map[string]string
+			from, to string
+		}{
+			{layerNames.types, capitalize(domainName) + suffix, naming.InterfaceName(domainName, layer)},
+			{layerNames.structs, domainName + suffix, naming.StructName(domainName, layer)},
+			{layerNames.types, "New" + capitalize(domainName) + suffix, naming.ConstructorName(domainName, layer)},
+		}
+		for _, rename := range renames {
+			if other, ok := declared[rename.to]; ok && flatDomain {
+				return fmt.Errorf("naming templates derive %s for both %s and %s, which --flat declares in one package", rename.to, other, rename.from)
+			}
+			declared[rename.to] = rename.from
+			if rename.from != rename.to {
+				rename.names[rename.from] = rename.to
+			}
+		}
+	}
+	return nil
+}
+
+// renameLayerTypes applies layerNames to a formatted Go source, comments
+// included
+func renameLayerTypes(src []byte) ([]byte, error) {
+	if len(layerNames.types) == 0 && len(layerNames.structs) == 0 {
+		return src, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	renameStruct := func(expr ast.Expr) {
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		if ident, ok := expr.(*ast.Ident); ok {
+			if name, ok := layerNames.structs[ident.Name]; ok {
+				ident.Name = name
+			}
+		}
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.Ident:
+			if name, ok := layerNames.types[n.Name]; ok {
+				n.Name = name
+			}
+		case *ast.TypeSpec:
+			renameStruct(n.Name)
+		case *ast.FuncDecl:
+			if n.Recv != nil {
+				for _, field := range n.Recv.List {
+					renameStruct(field.Type)
+				}
+			}
+		case *ast.CompositeLit:
+			if n.Type != nil {
+				renameStruct(n.Type)
+			}
+		}
+		return true
+	})
+
+	renames := make(map[string]string)
+	for _, names := range []map[string]string{layerNames.types, layerNames.structs} {
+		for from, to := range names {
+			renames[from] = to
+		}
+	}
+	words := make([]string, 0, len(renames))
+	for from := range renames {
+		words = append(words, regexp.QuoteMeta(from))
+	}
+	sort.Strings(words)
+	pattern := regexp.MustCompile(`\b(` + strings.Join(words, "|") + `)\b`)
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			comment.Text = pattern.ReplaceAllStringFunc(comment.Text, func(word string) string { return renames[word] })
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
@@ -30,16 +35,53 @@ Available Rules:
 - R03: Constructor patterns (returning interfaces) [default: warning]
 - R04: Domain boundaries (clean layer separation) [default: info]
 - R05: Centralized configuration (internal/config package) [default: error]
-- R06: Systematic error handling (internal/errors package) [default: error]`,
+- R06: Systematic error handling (internal/errors package) [default: error]
+- R07: Error mapping (not-found errors are not wrapped as internal) [default: warning]
+- R08: Domain independence (no import cycles between domains) [default: warning]
+- R09: Early return (handlers return after writing an error response) [default: warning]
+- R10: Interface placement (interfaces declared next to their only implementation) [default: off]
+- R11: Model leakage (handlers serialize responses, not models) [default: warning]
+- R12: File naming (layer files are named after their primary type) [default: info]
+- R13: Bind errors (request binding errors are checked before use) [default: warning]
+- R14: Repository interface (repositories are exposed through an exported interface) [default: warning]
+- R15: Receiver names (methods of a type use the same receiver name) [default: off]
+- R16: No panics (services, repositories and handlers return errors instead of crashing) [default: warning]
+- R17: Context propagation (handlers and services pass the request context down) [default: warning]
+- R18: Single service (handlers depend on the service of one domain) [default: warning]
+- R19: Layer direction (layers do not import the layers above them) [default: warning]
+- R20: Rows close (repositories defer closing the rows of their queries) [default: warning]
+- R21: Hardcoded secrets (no credentials in string literals) [default: off]
+- R22: Doc comments (exported declarations of the domains are documented) [default: off]
+- R23: Body decoding (handlers decode JSON bodies rejecting an empty one) [default: off]`,
 	Version: "0.0.3",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(importPathStyles, importPathStyle) {
+			return fmt.Errorf("unsupported --import-path-style %q (expected %s)", importPathStyle, strings.Join(importPathStyles, ", "))
+		}
+		return nil
+	},
 }
 
 func Execute() error {
+	rootCmd.SilenceUsage = os.Getenv(upgradeReplayEnv) != ""
 	return rootCmd.Execute()
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&importPathStyle, "import-path-style", "grouped", "Imports of generated Go files: grouped (standard library, third-party, module), goimports (grouped, then goimports -local <module> when on PATH) or none (as templated)")
+
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(addDomainCmd)
+	rootCmd.AddCommand(addFieldCmd)
+	rootCmd.AddCommand(addMiddlewareCmd)
+	rootCmd.AddCommand(addRuleCmd)
+	rootCmd.AddCommand(genSeedCmd)
+	rootCmd.AddCommand(genClientCmd)
+	rootCmd.AddCommand(genArchTestCmd)
+	rootCmd.AddCommand(introspectCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(upgradeCmd)
 }
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+var statsJSON bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the architecture of the current project",
+	Long: `Print a dashboard of the project's architectural health: its domains,
+which layers they implement, interfaces versus structs, exported versus
+unexported types, and the findings of each enabled GEAR rule.
+
+Compliance is the share of Go files without any finding. Exclusions and
+rule severities come from .gearrc, as with gear validate.
+
+Examples:
+  gear stats                  # Text dashboard
+  gear stats --json           # Machine-readable stats
+  gear stats --exclude tools  # Leave tools/ out of the counts`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return projectStatistics()
+	},
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the stats as JSON")
+	statsCmd.Flags().StringSliceVarP(&excludeDirs, "exclude", "e", []string{}, "Comma-separated list of directories to exclude from the stats")
+}
+
+// ProjectStats is the summary gear stats prints
+type ProjectStats struct {
+	Domains    []string       `json:"domains"`
+	Files      int            `json:"files"`
+	Layers     map[string]int `json:"layers"` // domains implementing each layer
+	Types      TypeStats      `json:"types"`
+	Rules      []RuleStats    `json:"rules"`
+	Compliance float64        `json:"compliance"` // percentage of files without findings
+}
+
+// TypeStats counts the type declarations of a project
+type TypeStats struct {
+	Total      int `json:"total"`
+	Interfaces int `json:"interfaces"`
+	Structs    int `json:"structs"`
+	Exported   int `json:"exported"`
+	Unexported int `json:"unexported"`
+}
+
+// RuleStats counts the findings of one enabled rule by severity
+type RuleStats struct {
+	Rule     string `json:"rule"`
+	Name     string `json:"name"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+	Info     int    `json:"info"`
+}
+
+func projectStatistics() error {
+	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
+		return fmt.Errorf("not in a Go project directory (go.mod not found)")
+	}
+
+	config, err := lint.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load .gearrc: %w", err)
+	}
+	opts, err := applyGearConfig(config, io.Discard)
+	if err != nil {
+		return err
+	}
+
+	project, err := lint.ParseProject(".", opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse project: %w", err)
+	}
+
+	stats := collectStats(project.Packages)
+
+	rules := lint.EnabledRules(opts.Severities)
+	findings := runRules(rules, project, opts, io.Discard)
+	byRule := make(map[string]*RuleStats)
+	for _, rule := range rules {
+		stats.Rules = append(stats.Rules, RuleStats{Rule: lint.RuleID(rule.Name), Name: rule.Description})
+	}
+	for i := range stats.Rules {
+		byRule[stats.Rules[i].Rule] = &stats.Rules[i]
+	}
+
+	flagged := make(map[string]bool)
+	for _, finding := range findings {
+		if rule, ok := byRule[lint.RuleID(finding.Rule)]; ok {
+			switch finding.Severity {
+			case "error":
+				rule.Errors++
+			case "warning":
+				rule.Warnings++
+			case "info":
+				rule.Info++
+			}
+		}
+		flagged[finding.File] = true
+	}
+
+	compliant := 0
+	for _, pkg := range project.Packages {
+		for path := range pkg.Files {
+			if !flagged[path] {
+				compliant++
+			}
+		}
+	}
+	if stats.Files > 0 {
+		stats.Compliance = float64(compliant) * 100 / float64(stats.Files)
+	}
+
+	if statsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(stats); err != nil {
+			return fmt.Errorf("failed to encode stats: %w", err)
+		}
+		return nil
+	}
+
+	printStats(stats, compliant)
+	return nil
+}
+
+// collectStats counts the domains, layers and type declarations of the parsed packages
+func collectStats(pkgs map[string]*ast.Package) ProjectStats {
+	stats := ProjectStats{Domains: []string{}, Layers: make(map[string]int)}
+	domainLayers := make(map[string]map[string]bool)
+
+	for _, pkg := range pkgs {
+		for path, file := range pkg.Files {
+			stats.Files++
+
+			domain := lint.DomainOfPath(filepath.ToSlash(path), "pkg/")
+			if domain != "" && !strings.HasSuffix(domain, ".go") && domainLayers[domain] == nil {
+				domainLayers[domain] = make(map[string]bool)
+			}
+
+			for _, decl := range file.Decls {
+				if layer := lint.LayerAt(path, file, decl.Pos()); layer != "" && domainLayers[domain] != nil {
+					domainLayers[domain][layer] = true
+				}
+
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					stats.Types.Total++
+					switch typeSpec.Type.(type) {
+					case *ast.InterfaceType:
+						stats.Types.Interfaces++
+					case *ast.StructType:
+						stats.Types.Structs++
+					}
+					if typeSpec.Name.IsExported() {
+						stats.Types.Exported++
+					} else {
+						stats.Types.Unexported++
+					}
+				}
+			}
+		}
+	}
+
+	for domain, layers := range domainLayers {
+		stats.Domains = append(stats.Domains, domain)
+		for layer := range layers {
+			stats.Layers[layer]++
+		}
+	}
+	sort.Strings(stats.Domains)
+
+	return stats
+}
+
+func printStats(stats ProjectStats, compliant int) {
+	fmt.Println("📊 GEAR project stats")
+	fmt.Println()
+
+	domains := "none"
+	if len(stats.Domains) > 0 {
+		domains = strings.Join(stats.Domains, ", ")
+	}
+	fmt.Printf("Domains: %d (%s)\n", len(stats.Domains), domains)
+	fmt.Printf("Files:   %d\n", stats.Files)
+	fmt.Printf("Types:   %d (%d interfaces, %d structs; %d exported, %d unexported)\n",
+		stats.Types.Total, stats.Types.Interfaces, stats.Types.Structs, stats.Types.Exported, stats.Types.Unexported)
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LAYER\tDOMAINS")
+	for _, layer := range lint.LayerNames {
+		fmt.Fprintf(w, "%s\t%d/%d\n", layer, stats.Layers[layer], len(stats.Domains))
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tERRORS\tWARNINGS\tINFO\tNAME")
+	for _, rule := range stats.Rules {
+		name, _, _ := strings.Cut(rule.Name, ":")
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", rule.Rule, rule.Errors, rule.Warnings, rule.Info, name)
+	}
+	w.Flush()
+
+	fmt.Printf("\n📈 Compliance: %.1f%% of files without findings (%d/%d)\n", stats.Compliance, compliant, stats.Files)
+}
@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var upgradeDryRun bool
+
+// upgradeReplayEnv is set for the commands upgrade replays, which then
+// report a failure without printing their usage
+const upgradeReplayEnv = "GEAR_UPGRADE_REPLAY"
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade generated files to the current GEAR templates",
+	Long: `Bring files generated by an older gear version up to date with the
+current templates.
+
+Every generated file is recorded in .gear/manifest.json with its hash and
+the command that produced it. upgrade replays those commands with the
+current templates and, for each file that changed, shows a diff and asks
+for confirmation before overwriting it.
+
+Files whose hash no longer matches the manifest were edited by hand and
+are never touched. Domain files from projects generated before the
+manifest existed are detected by their layout (pkg/<domain>/<layer>) and
+offered with a warning, since gear cannot tell whether they were edited.
+
+Commands the current gear can no longer replay, e.g. because a related
+domain was removed, are reported as skipped with the reason, leaving their
+files as they are.
+
+Examples:
+  gear upgrade            # Review and apply template updates file by file
+  gear upgrade --dry-run  # Only show what would change`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return upgradeProject()
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Show the diffs without writing any file")
+}
+
+// upgradeCandidate is a generated file that may be regenerated; Hash is
+// empty for files detected by layout rather than recorded in the manifest
+type upgradeCandidate struct {
+	Path    string
+	Hash    string
+	Command []string
+}
+
+func upgradeProject() error {
+	fmt.Println("🔄 Checking generated files against current templates...")
+
+	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
+		return fmt.Errorf("not in a Go project directory (go.mod not found)")
+	}
+
+	manifest, err := loadManifest(".")
+	if err != nil {
+		return err
+	}
+
+	candidates := collectUpgradeCandidates(manifest)
+	if len(candidates) == 0 {
+		fmt.Println("ℹ️  No generated files found")
+		return nil
+	}
+
+	// Replay each generating command once and compare all of its files
+	groups := make(map[string][]upgradeCandidate)
+	var commands []string
+	for _, candidate := range candidates {
+		key := strings.Join(candidate.Command, " ")
+		if groups[key] == nil {
+			commands = append(commands, key)
+		}
+		groups[key] = append(groups[key], candidate)
+	}
+	sort.Strings(commands)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate gear executable: %w", err)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	upgraded, skipped := 0, 0
+
+	for _, key := range commands {
+		group := groups[key]
+		rendered, err := renderCommand(executable, group[0].Command)
+		if err != nil {
+			fmt.Printf("⏭️  gear %s - skipped: %v\n", key, err)
+			skipped += len(group)
+			continue
+		}
+
+		for _, candidate := range group {
+			current, err := os.ReadFile(candidate.Path)
+			if err != nil {
+				fmt.Printf("⏭️  %s - missing, skipping\n", candidate.Path)
+				skipped++
+				continue
+			}
+
+			if candidate.Hash != "" && hashContent(string(current)) != candidate.Hash {
+				fmt.Printf("⏭️  %s - modified since generation, skipping\n", candidate.Path)
+				skipped++
+				continue
+			}
+
+			latest, ok := rendered[candidate.Path]
+			if !ok {
+				fmt.Printf("⏭️  %s - no longer generated by 'gear %s', skipping\n", candidate.Path, key)
+				skipped++
+				continue
+			}
+
+			if latest == string(current) {
+				continue
+			}
+
+			fmt.Printf("\n📝 %s (gear %s)\n", candidate.Path, key)
+			if candidate.Hash == "" {
+				fmt.Println("⚠️  Not recorded in the manifest - any manual edits in this file will be lost")
+			}
+			printDiff(string(current), latest)
+
+			if upgradeDryRun {
+				continue
+			}
+
+			fmt.Print("Apply this change? (y/N): ")
+			response, _ := reader.ReadString('\n')
+			response = strings.TrimSpace(response)
+			if response != "y" && response != "Y" {
+				skipped++
+				continue
+			}
+
+			if err := os.WriteFile(candidate.Path, []byte(latest), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", candidate.Path, err)
+			}
+			manifest.Files[candidate.Path] = GeneratedFile{
+				Hash:    hashContent(latest),
+				Command: candidate.Command,
+			}
+			upgraded++
+		}
+	}
+
+	if upgraded > 0 {
+		if err := saveManifest(".", manifest); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\n✅ Upgrade finished: %d upgraded, %d skipped\n", upgraded, skipped)
+	return nil
+}
+
+// collectUpgradeCandidates returns the files recorded in the manifest plus
+// domain files recognized by their layout in projects predating it
+func collectUpgradeCandidates(manifest *GenerationManifest) []upgradeCandidate {
+	var candidates []upgradeCandidate
+	for path, file := range manifest.Files {
+		if len(file.Command) == 0 {
+			continue
+		}
+		candidates = append(candidates, upgradeCandidate{
+			Path:    path,
+			Hash:    file.Hash,
+			Command: file.Command,
+		})
+	}
+
+	domainDirs, _ := os.ReadDir("pkg")
+	for _, entry := range domainDirs {
+		if !entry.IsDir() {
+			continue
+		}
+
+		domainName := entry.Name()
+		layerFiles := []string{
+			filepath.Join("pkg", domainName, "model", domainName+".go"),
+			filepath.Join("pkg", domainName, "repository", domainName+"_repository.go"),
+			filepath.Join("pkg", domainName, "service", domainName+"_service.go"),
+			filepath.Join("pkg", domainName, "handler", domainName+"_handler.go"),
+		}
+
+		for _, layerFile := range layerFiles {
+			path := filepath.ToSlash(layerFile)
+			if _, tracked := manifest.Files[path]; tracked {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			candidates = append(candidates, upgradeCandidate{
+				Path:    path,
+				Command: []string{"add-domain", domainName},
+			})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Path < candidates[j].Path
+	})
+
+	return candidates
+}
+
+// renderCommand runs a gear command in a scratch directory seeded with the
+// project's go.mod, .gearrc and pkg/ tree, returning the generated files by
+// path. The domains are seeded since --belongs-to and --has-many read the
+// models of the related domains.
+func renderCommand(executable string, command []string) (map[string]string, error) {
+	scratchDir, err := os.MkdirTemp("", "gear-upgrade-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratchDir)
+
+	if command[0] != "init" {
+		for _, seed := range []string{"go.mod", ".gearrc"} {
+			data, err := os.ReadFile(seed)
+			if err != nil {
+				continue
+			}
+			if err := os.WriteFile(filepath.Join(scratchDir, seed), data, 0644); err != nil {
+				return nil, fmt.Errorf("failed to seed %s: %w", seed, err)
+			}
+		}
+		if err := copyTree("pkg", filepath.Join(scratchDir, "pkg")); err != nil {
+			return nil, fmt.Errorf("failed to seed pkg: %w", err)
+		}
+	}
+
+	replay := exec.Command(executable, command...)
+	replay.Dir = scratchDir
+	replay.Env = append(os.Environ(), upgradeReplayEnv+"=1")
+	if output, err := replay.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("could not regenerate (%s)", replayError(string(output), err))
+	}
+
+	// init creates the project in a subdirectory; locate the replayed root
+	// by its manifest
+	root := ""
+	filepath.WalkDir(scratchDir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && root == "" && strings.HasSuffix(filepath.ToSlash(path), manifestPath) {
+			root = filepath.Dir(filepath.Dir(path))
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if root == "" {
+		return nil, fmt.Errorf("replaying 'gear %s' produced no files", strings.Join(command, " "))
+	}
+
+	replayed, err := loadManifest(root)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := make(map[string]string)
+	for path := range replayed.Files {
+		data, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			continue
+		}
+		rendered[path] = string(data)
+	}
+
+	return rendered, nil
+}
+
+// replayError returns the error a failed replay printed, falling back to
+// its exit status
+func replayError(output string, err error) string {
+	reason := err.Error()
+	for _, line := range strings.Split(output, "\n") {
+		if message, ok := strings.CutPrefix(line, "Error: "); ok {
+			reason = strings.TrimSpace(message)
+		}
+	}
+	return reason
+}
+
+// copyTree copies the regular files under src to dst, doing nothing when
+// src does not exist
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && path == src {
+			return nil
+		}
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
+// printDiff prints a line diff between two versions of a file, collapsing
+// long runs of unchanged lines
+func printDiff(oldContent, newContent string) {
+	oldLines := strings.Split(oldContent, "\n")
+	newLines := strings.Split(newContent, "\n")
+
+	// Longest common subsequence table, filled from the end
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	const context = 2
+	var unchanged []string
+	flushUnchanged := func(last bool) {
+		if len(unchanged) > 2*context+1 || (last && len(unchanged) > context) {
+			for _, line := range unchanged[:context] {
+				fmt.Printf("  %s\n", line)
+			}
+			fmt.Println("  ...")
+			if !last {
+				for _, line := range unchanged[len(unchanged)-context:] {
+					fmt.Printf("  %s\n", line)
+				}
+			}
+		} else {
+			for _, line := range unchanged {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+		unchanged = nil
+	}
+
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			unchanged = append(unchanged, oldLines[i])
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			flushUnchanged(false)
+			fmt.Printf("+ %s\n", newLines[j])
+			j++
+		default:
+			flushUnchanged(false)
+			fmt.Printf("- %s\n", oldLines[i])
+			i++
+		}
+	}
+	flushUnchanged(true)
+}
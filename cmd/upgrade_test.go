@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The test binary stands in for the gear executable: upgrade replays the
+// recorded commands through os.Executable
+func TestMain(m *testing.M) {
+	if os.Getenv("GEAR_TEST_AS_CLI") == "1" {
+		if err := Execute(); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runGear runs a gear command in dir, failing the test when it fails
+func runGear(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gear := exec.Command(executable, args...)
+	gear.Dir = dir
+	gear.Env = append(os.Environ(), "GEAR_TEST_AS_CLI=1")
+	if output, err := gear.CombinedOutput(); err != nil {
+		t.Fatalf("gear %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		done <- string(data)
+	}()
+	fn()
+	writer.Close()
+	return <-done
+}
+
+// upgradeDryRunIn runs gear upgrade --dry-run in dir
+func upgradeDryRunIn(t *testing.T, dir string) (string, error) {
+	t.Helper()
+	t.Chdir(dir)
+	t.Setenv("GEAR_TEST_AS_CLI", "1")
+	dryRun := upgradeDryRun
+	upgradeDryRun = true
+	defer func() { upgradeDryRun = dryRun }()
+
+	var err error
+	output := captureStdout(t, func() { err = upgradeProject() })
+	return output, err
+}
+
+func TestUpgradeReplaysRelations(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/demo\n\ngo 1.22\n\nrequire gorm.io/gorm v1.25.12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGear(t, dir, "add-domain", "organization")
+	runGear(t, dir, "add-domain", "user", "--belongs-to", "organization")
+
+	output, err := upgradeDryRunIn(t, dir)
+	if err != nil {
+		t.Fatalf("upgrade: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "0 upgraded, 0 skipped") {
+		t.Fatalf("upgrade did not replay every command cleanly:\n%s", output)
+	}
+}
+
+func TestUpgradeSkipsCommandsThatCannotBeReplayed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/demo\n\ngo 1.22\n\nrequire gorm.io/gorm v1.25.12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGear(t, dir, "add-domain", "organization")
+
+	// user was generated with a relation to a domain deleted since
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest.Files["pkg/user/model/user.go"] = GeneratedFile{
+		Hash:    hashContent("package model\n"),
+		Command: []string{"add-domain", "user", "--belongs-to", "team"},
+	}
+	if err := saveManifest(dir, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := upgradeDryRunIn(t, dir)
+	if err != nil {
+		t.Fatalf("upgrade: %v\n%s", err, output)
+	}
+	skipped := "gear add-domain user --belongs-to team - skipped: could not regenerate (--belongs-to team: domain team not found, add it first)"
+	if !strings.Contains(output, skipped) {
+		t.Fatalf("missing %q in:\n%s", skipped, output)
+	}
+	if !strings.Contains(output, "0 upgraded, 1 skipped") {
+		t.Fatalf("the other commands were not replayed cleanly:\n%s", output)
+	}
+}
@@ -2,11 +2,31 @@ package cmd
 
 import (
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// writeFile writes a generated file, formatting Go sources with gofmt first so
+// templates can never produce code that doesn't parse or isn't canonical
 func writeFile(fileName, content string) error {
+	if filepath.Ext(fileName) == ".go" {
+		formatted, err := format.Source([]byte(content))
+		if err != nil {
+			return fmt.Errorf("generated %s is not valid Go (this is a gear bug, please report it): %w", fileName, err)
+		}
+		formatted, err = renameLayerTypes(formatted)
+		if err != nil {
+			return fmt.Errorf("failed to apply the naming templates to %s: %w", fileName, err)
+		}
+		formatted, err = arrangeImports(formatted)
+		if err != nil {
+			return fmt.Errorf("failed to arrange the imports of %s: %w", fileName, err)
+		}
+		content = string(formatted)
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(fileName)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -24,6 +44,45 @@ func writeFile(fileName, content string) error {
 		return fmt.Errorf("failed to write to file %s: %w", fileName, err)
 	}
 
+	if err := recordGeneratedFile(fileName, content); err != nil {
+		return err
+	}
+	writtenFiles = append(writtenFiles, fileName)
 	return nil
 }
 
+// detectHandler infers the web framework of the current project from go.mod:
+// "none" when it requires none of the supported frameworks, as in projects
+// created with init --minimal, and gin when there is no go.mod to read
+func detectHandler() string {
+	data, err := os.ReadFile("go.mod")
+	if err != nil {
+		return "gin"
+	}
+
+	content := string(data)
+	switch {
+	case strings.Contains(content, "github.com/labstack/echo"):
+		return "echo"
+	case strings.Contains(content, "github.com/gofiber/fiber"):
+		return "fiber"
+	case strings.Contains(content, "github.com/gorilla/mux"):
+		return "mux"
+	case strings.Contains(content, "github.com/go-chi/chi"):
+		return "chi"
+	case strings.Contains(content, "github.com/gin-gonic/gin"):
+		return "gin"
+	default:
+		return "none"
+	}
+}
+
+// detectORM infers the ORM of the current project from go.mod: gorm when it
+// requires gorm, as generated repositories do, and "none" otherwise
+func detectORM() string {
+	data, err := os.ReadFile("go.mod")
+	if err != nil || strings.Contains(string(data), "gorm.io/gorm") {
+		return "gorm"
+	}
+	return "none"
+}
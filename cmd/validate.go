@@ -1,43 +1,42 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
+	"io"
+	"maps"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
-)
-
-type ValidationRule struct {
-	Name        string
-	Description string
-	Check       func(pkg *ast.Package, files map[string]*ast.File) []ValidationError
-}
-
-type ValidationError struct {
-	Rule     string
-	File     string
-	Line     int
-	Column   int
-	Message  string
-	Severity string // "error", "warning", "info"
-}
 
-// GearConfig represents the .gearrc configuration file
-type GearConfig struct {
-	Exclude []string          `yaml:"exclude"`
-	Rules   map[string]string `yaml:"rules,omitempty"`
-}
+	"github.com/gomessguii/gear/pkg/lint"
+)
 
 var (
-	excludeDirs []string
+	excludeDirs      []string
+	summaryTable     bool
+	diffBase         string
+	outputFormat     string
+	includeGenerated bool
+	watchMode        bool
+	quietMode        bool
+	noFail           bool
+	failOn           string
+	noCache          bool
+	severityFlags    []string
+	configPath       string
 )
 
+// validationCachePath is where validate keeps the findings of its last run,
+// relative to the project root
+const validationCachePath = ".gear/cache.json"
+
+// severityRanks orders the severities --fail-on compares findings against
+var severityRanks = map[string]int{"info": 1, "warning": 2, "error": 3}
+
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate GEAR rule compliance in the current project",
@@ -50,14 +49,60 @@ Available Rules:
 - R04: Domain boundaries (clean layer separation) [default: info]
 - R05: Centralized configuration (internal/config package) [default: error]
 - R06: Systematic error handling (internal/errors package) [default: error]
+- R07: Error mapping (not-found errors are not wrapped as internal) [default: warning]
+- R08: Domain independence (no import cycles between domains) [default: warning]
+- R09: Early return (handlers return after writing an error response) [default: warning]
+- R10: Interface placement (interfaces declared next to their only implementation) [default: off]
+- R11: Model leakage (handlers serialize responses, not models) [default: warning]
+- R12: File naming (layer files are named after their primary type) [default: info]
+- R13: Bind errors (request binding errors are checked before use) [default: warning]
+- R14: Repository interface (repositories are exposed through an exported interface) [default: warning]
+- R15: Receiver names (methods of a type use the same receiver name) [default: off]
+- R16: No panics (services, repositories and handlers return errors instead of crashing) [default: warning]
+- R17: Context propagation (handlers and services pass the request context down) [default: warning]
+- R18: Single service (handlers depend on the service of one domain) [default: warning]
+- R19: Layer direction (layers do not import the layers above them) [default: warning]
+- R20: Rows close (repositories defer closing the rows of their queries) [default: warning]
+- R21: Hardcoded secrets (no credentials in string literals) [default: off]
+- R22: Doc comments (exported declarations of the domains are documented) [default: off]
+- R23: Body decoding (handlers decode JSON bodies rejecting an empty one) [default: off]
 
 Examples:
   gear validate                                    # Validate entire project
   gear validate --exclude vendor,test             # Exclude vendor and test directories
   gear validate --exclude pkg/external,migration  # Exclude specific paths
+  gear validate --summary-table                    # Append a per-rule findings table
+  gear validate --diff origin/main                 # Only report findings on lines changed since origin/main
+  gear validate --format json                      # Machine-readable findings with documentation links
+  gear validate --format sarif > gear.sarif        # SARIF 2.1.0 for GitHub code scanning
+  gear validate --include-generated                # Also validate generated files (skipped by default)
+  gear validate --watch                            # Re-validate on every .go file change
+  gear validate --quiet                            # Findings and summary only
+  gear validate --format json --no-fail            # Report only, always exit 0
+  gear validate --fail-on warning                  # Also fail on warnings
+  gear validate --no-cache                         # Re-check every file, ignoring .gear/cache.json
+  gear validate --severity-override R15=error      # Enforce a rule for this run only (repeatable)
+  gear validate --config ../shared/gearrc.yaml     # Read the configuration from another file
+
+Findings are cached in .gear/cache.json by file size and modification time.
+Rules that only look at one file re-check the files changed since the last
+run; rules that look across files re-check the whole project when any file
+changed. The cache is discarded when a setting findings depend on changes:
+the exclude, rules, allow_list, naming or doc_comments of .gearrc, the
+--exclude, --include-generated or --severity-override flags, go.mod or the
+gear version. Other edits to .gearrc, such as comments, keep it.
+
+Rule severities are error, warning, info or off. A rule's severity comes
+from --severity-override R01=error when given, then from the rules of
+.gearrc, then from its default; optional rules only run once given one.
 
 Configuration:
-  Create a .gearrc file in your project root to set default options:
+  Create a .gearrc file in your project root to set default options, or
+  point --config at a file in the same format elsewhere, e.g. one shared by
+  the services of a monorepo. Paths in it stay relative to the current
+  directory.
+  
+  version: 1        # .gearrc format (files without it are read as version 0)
   
   exclude:
     - "vendor"
@@ -71,86 +116,153 @@ Configuration:
     R03: "warning"  # Constructor patterns 
     R04: "info"     # Domain boundaries
     R05: "error"    # Centralized configuration
-    R06: "error"    # Systematic error handling`,
+    R06: "error"    # Systematic error handling
+    R07: "warning"  # Error mapping
+    R08: "warning"  # Domain independence
+    R09: "warning"  # Early return
+    R10: "off"      # Interface placement (opt-in: set a severity to enable)
+    R11: "warning"  # Model leakage
+    R12: "info"     # File naming
+    R13: "warning"  # Bind errors
+    R14: "warning"  # Repository interface
+    R15: "off"      # Receiver names (opt-in: set a severity to enable)
+    R16: "warning"  # No panics
+    R17: "warning"  # Context propagation
+    R18: "warning"  # Single service
+    R19: "warning"  # Layer direction
+    R20: "warning"  # Rows close
+    R21: "off"      # Hardcoded secrets (opt-in: set a severity to enable)
+    R22: "off"      # Doc comments (opt-in: set a severity to enable)
+    R23: "off"      # Body decoding (opt-in: set a severity to enable)
+
+  allow_list:       # Extra data-struct naming conventions exempt from R01
+    suffixes:
+      - "VO"
+      - "Record"
+    prefixes:
+      - "Raw"
+    secrets:        # Regular expressions of string literals R21 accepts
+      - "^AKIA[0-9A-Z]+EXAMPLE$"
+
+  doc_comments:     # Layers R22 checks, all of them when empty
+    layers: [service, repository]
+
+  naming:           # Layer type names add-domain generates and R03/R12/R14/R18 expect
+    interface: "I{Domain}{Layer}"   # default {Domain}{Layer}
+    struct: "{domain}{Layer}"       # default {domain}{Layer}
+    constructor: "New{Domain}{Layer}"
+    suffixes:
+      service: "Svc"                # {Layer} of services, default Service`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return validateProject()
+		if watchMode {
+			return watchProject()
+		}
+
+		failing, err := validateProject()
+		if err != nil {
+			return err
+		}
+		if failing > 0 && !noFail {
+			os.Exit(1)
+		}
+		return nil
 	},
 }
 
-func validateProject() error {
-	fmt.Println("🔍 Validating GEAR compliance...")
+// validateProject runs the rules and prints the report, returning the number
+// of findings at or above the --fail-on severity
+func validateProject() (int, error) {
+	if outputFormat != "text" && outputFormat != "json" && outputFormat != "sarif" {
+		return 0, fmt.Errorf("unsupported format %q (expected text, json or sarif)", outputFormat)
+	}
+	if _, ok := severityRanks[failOn]; !ok && failOn != "none" {
+		return 0, fmt.Errorf("unsupported --fail-on %q (expected error, warning, info or none)", failOn)
+	}
+
+	// Progress goes to stderr with --format json or sarif so stdout stays parseable
+	var progress io.Writer = os.Stdout
+	switch {
+	case quietMode:
+		progress = io.Discard
+	case outputFormat != "text":
+		progress = os.Stderr
+	}
+
+	fmt.Fprintln(progress, "🔍 Validating GEAR compliance...")
 
 	// Check if we're in a Go project
 	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
-		return fmt.Errorf("not in a Go project directory (go.mod not found)")
+		return 0, fmt.Errorf("not in a Go project directory (go.mod not found)")
 	}
 
-	// Load configuration from .gearrc if it exists
 	config, err := loadGearConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load .gearrc: %w", err)
+		return 0, err
 	}
 
-	// Merge CLI flags with config file (CLI flags take precedence)
-	if len(excludeDirs) == 0 && len(config.Exclude) > 0 {
-		excludeDirs = config.Exclude
-		fmt.Printf("📄 Loaded exclusions from .gearrc: %v\n", excludeDirs)
-	}
-
-	rules := []ValidationRule{
-		{
-			Name:        "R01-interface-contracts",
-			Description: "Interface contracts: exported interfaces + unexported structs",
-			Check:       validateInterfaceContracts,
-		},
-		{
-			Name:        "R02-interface-usage",
-			Description: "Interface usage: no pointer-to-interface anti-patterns",
-			Check:       validateInterfaceUsage,
-		},
-		{
-			Name:        "R03-constructor-patterns",
-			Description: "Constructor patterns: constructors return interfaces",
-			Check:       validateConstructorPatterns,
-		},
-		{
-			Name:        "R04-domain-boundaries",
-			Description: "Domain boundaries: clean layer separation",
-			Check:       validateDomainBoundaries,
-		},
-		{
-			Name:        "R05-centralized-config",
-			Description: "Centralized configuration: internal/config package exists",
-			Check:       validateCentralizedConfig,
-		},
-		{
-			Name:        "R06-systematic-errors",
-			Description: "Systematic error handling: internal/errors package exists",
-			Check:       validateSystematicErrors,
-		},
-	}
-
-	var allErrors []ValidationError
-
-	// Parse all Go files in the project
-	pkgs, err := parseProject()
+	opts, err := applyGearConfig(config, progress)
+	if err != nil {
+		return 0, err
+	}
+
+	// Unchanged files keep the findings of the previous run
+	cacheKey := lint.CacheKey(".", opts, rootCmd.Version)
+	cache := lint.NewCache(cacheKey)
+	if !noCache {
+		cache = lint.LoadCache(validationCachePath, cacheKey)
+	}
+
+	stamps, err := lint.StampFiles(".", opts)
 	if err != nil {
-		return fmt.Errorf("failed to parse project: %w", err)
+		return 0, fmt.Errorf("failed to scan project: %w", err)
+	}
+
+	allErrors, reused := cache.Reuse(stamps)
+	if reused {
+		fmt.Fprintln(progress, "📦 No files changed since the last run, reusing its findings")
+	} else {
+		// Parse all Go files in the project
+		project, err := lint.ParseProject(".", opts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse project: %w", err)
+		}
+
+		opts.Cache = cache
+		allErrors = runRules(lint.EnabledRules(opts.Severities), project, opts, progress)
+
+		if err := cache.Save(validationCachePath); err != nil {
+			fmt.Fprintf(progress, "⚠️  Findings not cached: %v\n", err)
+		}
+	}
+
+	// Restrict findings to changed lines when gating a branch
+	if diffBase != "" {
+		changed, err := changedLines(diffBase)
+		if err != nil {
+			fmt.Fprintf(progress, "⚠️  --diff ignored, reporting all findings: %v\n", err)
+		} else {
+			allErrors = filterToDiff(allErrors, changed)
+			fmt.Fprintf(progress, "📄 Reporting findings on lines changed since %s\n", diffBase)
+		}
 	}
 
-	// Run validation rules
-	for _, rule := range rules {
-		fmt.Printf("  Checking %s...\n", rule.Description)
-		for _, pkg := range pkgs {
-			errors := rule.Check(pkg, nil) // TODO: pass files map
-			allErrors = append(allErrors, errors...)
+	if outputFormat == "json" {
+		if err := printJSONReport(allErrors); err != nil {
+			return 0, err
+		}
+		return countFailing(allErrors), nil
+	}
+	if outputFormat == "sarif" {
+		if err := printSARIFReport(allErrors); err != nil {
+			return 0, err
 		}
+		return countFailing(allErrors), nil
 	}
 
 	// Report results
 	if len(allErrors) == 0 {
 		fmt.Println("✅ All GEAR rules validated successfully!")
-		return nil
+		return 0, nil
 	}
 
 	fmt.Printf("\n❌ Found %d GEAR compliance issues:\n\n", len(allErrors))
@@ -159,704 +271,212 @@ func validateProject() error {
 	warningCount := 0
 
 	for _, err := range allErrors {
+		hint := fmt.Sprintf(" (see gear explain %s)", lint.RuleID(err.Rule))
 		switch err.Severity {
 		case "error":
-			fmt.Printf("❌ [%s] %s:%d:%d - %s\n", err.Rule, err.File, err.Line, err.Column, err.Message)
+			fmt.Printf("❌ [%s] %s:%d:%d - %s%s\n", err.Rule, err.File, err.Line, err.Column, err.Message, hint)
 			errorCount++
 		case "warning":
-			fmt.Printf("⚠️  [%s] %s:%d:%d - %s\n", err.Rule, err.File, err.Line, err.Column, err.Message)
+			fmt.Printf("⚠️  [%s] %s:%d:%d - %s%s\n", err.Rule, err.File, err.Line, err.Column, err.Message, hint)
 			warningCount++
 		case "info":
-			fmt.Printf("ℹ️  [%s] %s:%d:%d - %s\n", err.Rule, err.File, err.Line, err.Column, err.Message)
+			fmt.Printf("ℹ️  [%s] %s:%d:%d - %s%s\n", err.Rule, err.File, err.Line, err.Column, err.Message, hint)
 		}
 	}
 
 	fmt.Printf("\nSummary: %d errors, %d warnings\n", errorCount, warningCount)
 
-	if errorCount > 0 {
-		os.Exit(1)
+	if summaryTable {
+		printSummaryTable(allErrors)
 	}
 
-	return nil
+	return countFailing(allErrors), nil
 }
 
-var globalFileSet *token.FileSet
-
-func parseProject() (map[string]*ast.Package, error) {
-	globalFileSet = token.NewFileSet()
-	packages := make(map[string]*ast.Package)
-
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip non-Go files and default excluded directories
-		if !strings.HasSuffix(path, ".go") ||
-			strings.Contains(path, "vendor/") ||
-			strings.Contains(path, ".git/") {
-			return nil
-		}
-
-		// Skip user-specified excluded paths and patterns
-		for _, excludePattern := range excludeDirs {
-			excludePattern = strings.TrimSpace(excludePattern)
-			if excludePattern == "" {
-				continue
-			}
-
-			// 1. Exact file name match (e.g., "main.go")
-			if filepath.Base(path) == excludePattern {
-				return nil
-			}
-
-			// 2. Directory path match (e.g., "vendor", "scripts")
-			if strings.Contains(path, excludePattern+"/") || strings.HasSuffix(path, "/"+excludePattern) {
-				return nil
-			}
-
-			// 3. Glob pattern match (e.g., "*_test.go", "*.pb.go")
-			if strings.Contains(excludePattern, "*") || strings.Contains(excludePattern, "?") {
-				// Match against filename only
-				if matched, err := filepath.Match(excludePattern, filepath.Base(path)); err == nil && matched {
-					return nil
-				}
-				// Match against relative path for patterns like "pkg/*_test.go"
-				if matched, err := filepath.Match(excludePattern, path); err == nil && matched {
-					return nil
-				}
-			}
-		}
-
-		// If this is a directory that should be skipped entirely, skip it
-		if info.IsDir() {
-			for _, excludeDir := range excludeDirs {
-				excludeDir = strings.TrimSpace(excludeDir)
-				if excludeDir != "" && strings.HasSuffix(path, excludeDir) {
-					return filepath.SkipDir
-				}
-			}
-		}
-
-		// Parse the file
-		src, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		file, err := parser.ParseFile(globalFileSet, path, src, parser.ParseComments)
-		if err != nil {
-			return err
-		}
-
-		// Group by package
-		pkgName := file.Name.Name
-		if packages[pkgName] == nil {
-			packages[pkgName] = &ast.Package{
-				Name:  pkgName,
-				Files: make(map[string]*ast.File),
-			}
-		}
-		packages[pkgName].Files[path] = file
-
-		return nil
-	})
-
-	return packages, err
-}
-
-func validateInterfaceContracts(pkg *ast.Package, files map[string]*ast.File) []ValidationError {
-	var errors []ValidationError
-
-	for filePath, file := range pkg.Files {
-		// Track types with their positions
-		type TypeInfo struct {
-			Name       string
-			IsExported bool
-			Position   token.Pos
-		}
-
-		var interfaces []TypeInfo
-		var structs []TypeInfo
-
-		// First pass: collect interfaces and structs with positions
-		for _, decl := range file.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok || genDecl.Tok != token.TYPE {
-				continue
-			}
-
-			for _, spec := range genDecl.Specs {
-				typeSpec, ok := spec.(*ast.TypeSpec)
-				if !ok {
-					continue
-				}
-
-				switch typeSpec.Type.(type) {
-				case *ast.InterfaceType:
-					interfaces = append(interfaces, TypeInfo{
-						Name:       typeSpec.Name.Name,
-						IsExported: typeSpec.Name.IsExported(),
-						Position:   typeSpec.Pos(),
-					})
-				case *ast.StructType:
-					structs = append(structs, TypeInfo{
-						Name:       typeSpec.Name.Name,
-						IsExported: typeSpec.Name.IsExported(),
-						Position:   typeSpec.Pos(),
-					})
-				}
-			}
-		}
-
-		// Check for exported structs (should be unexported in GEAR)
-		// BUT exclude models, DTOs, requests, responses, and configs
-		for _, structInfo := range structs {
-			if structInfo.IsExported && shouldBeUnexported(structInfo.Name, filePath, file) {
-				pos := globalFileSet.Position(structInfo.Position)
-				errors = append(errors, ValidationError{
-					Rule:     "R01-interface-contracts",
-					File:     filePath,
-					Line:     pos.Line,
-					Column:   pos.Column,
-					Message:  fmt.Sprintf("Struct '%s' is exported - GEAR prefers unexported structs with exported interfaces for service/business logic", structInfo.Name),
-					Severity: "warning",
-				})
-			}
-		}
-
-		// Check for unexported interfaces (should be exported in GEAR)
-		for _, interfaceInfo := range interfaces {
-			if !interfaceInfo.IsExported {
-				pos := globalFileSet.Position(interfaceInfo.Position)
-				errors = append(errors, ValidationError{
-					Rule:     "R01-interface-contracts",
-					File:     filePath,
-					Line:     pos.Line,
-					Column:   pos.Column,
-					Message:  fmt.Sprintf("Interface '%s' is unexported - GEAR requires exported interfaces", interfaceInfo.Name),
-					Severity: "error",
-				})
-			}
+// countFailing returns the number of findings at or above the --fail-on
+// severity; --fail-on none never fails
+func countFailing(findings []lint.ValidationError) int {
+	threshold, ok := severityRanks[failOn]
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, finding := range findings {
+		if severityRanks[finding.Severity] >= threshold {
+			count++
 		}
 	}
-
-	return errors
+	return count
 }
 
-// shouldBeUnexported determines if a struct should be unexported based on GEAR rules
-// Returns true only for service/business logic structs, false for models/DTOs/configs
-func shouldBeUnexported(structName, filePath string, file *ast.File) bool {
-	// If struct has no methods, it's a data structure and should be exported
-	if !structHasMethods(structName, file) {
-		return false
-	}
-
-	// Models, DTOs, requests, responses should remain exported
-	if isDataStruct(structName) {
-		return false
-	}
-
-	// Files in model/proto directories contain data structures
-	if strings.Contains(filePath, "/model/") ||
-		strings.Contains(filePath, "/proto/") ||
-		strings.Contains(filePath, "/dto/") ||
-		strings.Contains(filePath, "/client/") ||
-		strings.Contains(filePath, "/provider/") {
-		return false
+// loadGearConfig loads the file given with --config, or .gearrc from the
+// current directory if it exists
+func loadGearConfig() (*lint.Config, error) {
+	if configPath == "" {
+		config, err := lint.LoadConfig(".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load .gearrc: %w", err)
+		}
+		return config, nil
 	}
 
-	// Configuration structs should remain exported for ease of use
-	if strings.Contains(filePath, "/config/") || strings.HasSuffix(structName, "Config") {
-		return false
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("--config %s: file does not exist", configPath)
 	}
-
-	// Error types should remain exported
-	if strings.Contains(filePath, "/errors/") {
-		return false
+	config, err := lint.LoadConfigFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load --config: %w", err)
 	}
+	return config, nil
+}
 
-	// Service, handler, repository implementations should be unexported
-	if strings.Contains(filePath, "/service/") ||
-		strings.Contains(filePath, "/handler/") ||
-		strings.Contains(filePath, "/repository/") {
-		return true
+// configName names the config file the run reads in messages
+func configName() string {
+	if configPath != "" {
+		return configPath
 	}
-
-	// Default: check if it looks like a business logic struct
-	return !isDataStruct(structName)
+	return ".gearrc"
 }
 
-// isDataStruct checks if a struct name indicates it's a data structure (should be exported)
-func isDataStruct(name string) bool {
-	dataStructSuffixes := []string{
-		"Request", "Response", "Model", "DTO", "Data", "Entity",
-		"Config", "Settings", "Options", "Params", "Result", "Info",
-		"Status", "State", "Event", "Message", "Payload", "Body",
-		"Error", "Exception", "Notification", "Alert", "Report",
+// applyGearConfig merges .gearrc into the validation settings, CLI flags
+// taking precedence, checks its rule severities and returns the options of
+// the run
+func applyGearConfig(config *lint.Config, progress io.Writer) (lint.Options, error) {
+	if len(excludeDirs) == 0 && len(config.Exclude) > 0 {
+		excludeDirs = config.Exclude
+		fmt.Fprintf(progress, "📄 Loaded exclusions from %s: %v\n", configName(), excludeDirs)
 	}
 
-	for _, suffix := range dataStructSuffixes {
-		if strings.HasSuffix(name, suffix) {
-			return true
+	for id, severity := range config.Rules {
+		switch severity {
+		case "error", "warning", "info", "off":
+		default:
+			return lint.Options{}, fmt.Errorf("%s: rule %s has invalid severity %q (expected error, warning, info or off)", configName(), id, severity)
 		}
 	}
 
-	// Check for common data structure patterns
-	dataStructPrefixes := []string{
-		"Create", "Update", "Delete", "Get", "List", "Search",
+	overrides, err := parseSeverityOverrides(severityFlags)
+	if err != nil {
+		return lint.Options{}, err
 	}
 
-	for _, prefix := range dataStructPrefixes {
-		if strings.HasPrefix(name, prefix) {
-			return true
+	opts := config.Options()
+	opts.Exclude = excludeDirs
+	opts.IncludeGenerated = includeGenerated
+	if len(overrides) > 0 {
+		opts.Severities = maps.Clone(opts.Severities)
+		if opts.Severities == nil {
+			opts.Severities = make(map[string]string, len(overrides))
 		}
+		maps.Copy(opts.Severities, overrides)
+		fmt.Fprintf(progress, "🎚️  Severity overrides: %s\n", strings.Join(severityFlags, ", "))
 	}
-
-	return false
+	return opts, nil
 }
 
-// structHasMethods checks if a struct has any methods defined in the same file
-func structHasMethods(structName string, file *ast.File) bool {
-	for _, decl := range file.Decls {
-		funcDecl, ok := decl.(*ast.FuncDecl)
-		if !ok || funcDecl.Recv == nil {
-			continue
+// parseSeverityOverrides reads the RULE=severity values of
+// --severity-override, checking the rule IDs and severities
+func parseSeverityOverrides(values []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+	for _, value := range values {
+		id, severity, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --severity-override %q (expected RULE=severity, e.g. R01=error)", value)
 		}
-
-		// Check if this method belongs to our struct
-		for _, recv := range funcDecl.Recv.List {
-			switch recvType := recv.Type.(type) {
-			case *ast.Ident:
-				if recvType.Name == structName {
-					return true
-				}
-			case *ast.StarExpr:
-				if ident, ok := recvType.X.(*ast.Ident); ok && ident.Name == structName {
-					return true
-				}
-			}
+		rule, found := lint.LookupRule(strings.TrimSpace(id))
+		if !found {
+			return nil, fmt.Errorf("--severity-override %s: unknown rule %q (see gear explain)", value, id)
 		}
-	}
-	return false
-}
-
-func validateConstructorPatterns(pkg *ast.Package, files map[string]*ast.File) []ValidationError {
-	var errors []ValidationError
-
-	for filePath, file := range pkg.Files {
-		for _, decl := range file.Decls {
-			funcDecl, ok := decl.(*ast.FuncDecl)
-			if !ok {
-				continue
-			}
-
-			// Look for constructor functions (New* pattern)
-			if !strings.HasPrefix(funcDecl.Name.Name, "New") {
-				continue
-			}
-
-			// Skip error constructors and utility packages - they can return concrete types
-			if strings.Contains(filePath, "/errors/") ||
-				strings.Contains(filePath, "/utils/") ||
-				strings.Contains(filePath, "/util/") ||
-				strings.Contains(filePath, "/config/") ||
-				strings.Contains(filePath, "/model/") ||
-				strings.Contains(filePath, "/dto/") ||
-				strings.Contains(filePath, "/proto/") {
-				continue
-			}
-
-			// Check if it returns an interface
-			if funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) == 0 {
-				continue
-			}
-
-			returnType := funcDecl.Type.Results.List[0].Type
-
-			// Simple check - if it returns a pointer to struct, it's likely not following GEAR
-			if starExpr, ok := returnType.(*ast.StarExpr); ok {
-				if _, ok := starExpr.X.(*ast.Ident); ok {
-					pos := globalFileSet.Position(funcDecl.Pos())
-					errors = append(errors, ValidationError{
-						Rule:     "R02-constructor-patterns",
-						File:     filePath,
-						Line:     pos.Line,
-						Column:   pos.Column,
-						Message:  fmt.Sprintf("Constructor '%s' returns pointer to struct - GEAR constructors should return interfaces", funcDecl.Name.Name),
-						Severity: "warning",
-					})
-				}
-			}
+		switch severity = strings.TrimSpace(severity); severity {
+		case "error", "warning", "info", "off":
+		default:
+			return nil, fmt.Errorf("--severity-override %s: invalid severity %q (expected error, warning, info or off)", value, severity)
 		}
+		overrides[rule.ID] = severity
 	}
-
-	return errors
+	return overrides, nil
 }
 
-func validateDomainBoundaries(pkg *ast.Package, files map[string]*ast.File) []ValidationError {
-	var errors []ValidationError
-
-	// Check for expected domain structure
-	expectedDirs := []string{"handler", "service", "repository", "model"}
+// runRules runs the rules through lint.RunRules, drawing the progress meter
+func runRules(rules []lint.ValidationRule, project *lint.Project, opts lint.Options, progress io.Writer) []lint.ValidationError {
+	meter := newProgressMeter(project.FileCount() * len(rules))
+	opts.Progress = meterWriter{meter: meter, w: progress}
+	opts.Checked = meter.advance
 
-	for _, dir := range expectedDirs {
-		if _, err := os.Stat(filepath.Join("pkg", "*", dir)); os.IsNotExist(err) {
-			// This is a simple check - in reality, we'd want more sophisticated validation
-			continue
-		}
-	}
-
-	return errors
+	allErrors := lint.RunRules(rules, project, opts)
+	meter.clear()
+	return allErrors
 }
 
-func validateCentralizedConfig(pkg *ast.Package, files map[string]*ast.File) []ValidationError {
-	var errors []ValidationError
-
-	configPath := "internal/config"
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		errors = append(errors, ValidationError{
-			Rule:     "R04-centralized-config",
-			File:     configPath,
-			Message:  "Missing internal/config package - GEAR requires centralized configuration",
-			Severity: "error",
-		})
+// printJSONReport writes the findings and their counts as JSON to stdout
+func printJSONReport(allErrors []lint.ValidationError) error {
+	report := struct {
+		Findings []lint.ValidationError `json:"findings"`
+		Summary  map[string]int         `json:"summary"`
+	}{
+		Findings: allErrors,
+		Summary:  map[string]int{"error": 0, "warning": 0, "info": 0},
 	}
-
-	return errors
-}
-
-func validateSystematicErrors(pkg *ast.Package, files map[string]*ast.File) []ValidationError {
-	var errors []ValidationError
-
-	errorsPath := "internal/errors"
-	if _, err := os.Stat(errorsPath); os.IsNotExist(err) {
-		errors = append(errors, ValidationError{
-			Rule:     "R05-systematic-errors",
-			File:     errorsPath,
-			Message:  "Missing internal/errors package - GEAR requires systematic error handling",
-			Severity: "error",
-		})
+	if report.Findings == nil {
+		report.Findings = []lint.ValidationError{}
 	}
-
-	return errors
-}
-
-func validateInterfaceUsage(pkg *ast.Package, files map[string]*ast.File) []ValidationError {
-	var errors []ValidationError
-
-	for filePath, file := range pkg.Files {
-		// Build import map for this file
-		imports := make(map[string]string) // alias -> package path
-		for _, imp := range file.Imports {
-			path := strings.Trim(imp.Path.Value, `"`)
-			if imp.Name != nil {
-				// Named import: import foo "path/to/package"
-				imports[imp.Name.Name] = path
-			} else {
-				// Default import: import "path/to/package"
-				parts := strings.Split(path, "/")
-				packageName := parts[len(parts)-1]
-				imports[packageName] = path
-			}
-		}
-		// Walk through all declarations and expressions to find pointer-to-interface types
-		ast.Inspect(file, func(node ast.Node) bool {
-			switch n := node.(type) {
-			case *ast.StructType:
-				// Check struct fields for pointer-to-interface types
-				for _, field := range n.Fields.List {
-					if starExpr, ok := field.Type.(*ast.StarExpr); ok {
-						var typeName string
-						var isExternal bool
-
-						// Handle both local types (Ident) and external types (SelectorExpr)
-						switch x := starExpr.X.(type) {
-						case *ast.Ident:
-							typeName = x.Name
-							isExternal = false
-						case *ast.SelectorExpr:
-							// External package type like lead_service.StatusService
-							typeName = x.Sel.Name
-							isExternal = true
-						default:
-							continue
-						}
-
-						// Check if it's actually an interface
-						isInterface := false
-						if !isExternal {
-							// Local type - check in file scope
-							if obj := file.Scope.Lookup(typeName); obj != nil && obj.Kind == ast.Typ {
-								if typeSpec, ok := obj.Decl.(*ast.TypeSpec); ok {
-									if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
-										isInterface = true
-									}
-								}
-							}
-						} else {
-							// External type - try to resolve it by parsing the external package
-							if selectorExpr, ok := starExpr.X.(*ast.SelectorExpr); ok {
-								if pkgIdent, ok := selectorExpr.X.(*ast.Ident); ok {
-									packagePath, exists := imports[pkgIdent.Name]
-									if exists {
-										isInterface = isExternalInterface(packagePath, typeName)
-									}
-								}
-							}
-						}
-
-						if isInterface {
-							pos := globalFileSet.Position(starExpr.Pos())
-							var fieldName string
-							if len(field.Names) > 0 {
-								fieldName = field.Names[0].Name
-							} else {
-								fieldName = typeName
-							}
-							errors = append(errors, ValidationError{
-								Rule:     "R06-interface-usage",
-								File:     filePath,
-								Line:     pos.Line,
-								Column:   pos.Column,
-								Message:  fmt.Sprintf("Struct field '%s' has type '*%s' - pointer to interface is an anti-pattern, use '%s' instead", fieldName, typeName, typeName),
-								Severity: "error",
-							})
-						}
-					}
-				}
-			case *ast.StarExpr:
-				// Check if this is a pointer to an interface
-				if ident, ok := n.X.(*ast.Ident); ok {
-					// Look up the type in the file's scope
-					if obj := file.Scope.Lookup(ident.Name); obj != nil && obj.Kind == ast.Typ {
-						if typeSpec, ok := obj.Decl.(*ast.TypeSpec); ok {
-							if _, isInterface := typeSpec.Type.(*ast.InterfaceType); isInterface {
-								pos := globalFileSet.Position(n.Pos())
-								errors = append(errors, ValidationError{
-									Rule:     "R06-interface-usage",
-									File:     filePath,
-									Line:     pos.Line,
-									Column:   pos.Column,
-									Message:  fmt.Sprintf("Pointer to interface '*%s' is an anti-pattern - interfaces are already reference types", ident.Name),
-									Severity: "error",
-								})
-							}
-						}
-					}
-				}
-			case *ast.FuncDecl:
-				// Check function parameters for pointer-to-interface
-				if n.Type.Params != nil {
-					for _, param := range n.Type.Params.List {
-						if starExpr, ok := param.Type.(*ast.StarExpr); ok {
-							var typeName string
-							var isExternal bool
-
-							// Handle both local types (Ident) and external types (SelectorExpr)
-							switch x := starExpr.X.(type) {
-							case *ast.Ident:
-								typeName = x.Name
-								isExternal = false
-							case *ast.SelectorExpr:
-								// External package type like lead_handler.StatusHandler
-								typeName = x.Sel.Name
-								isExternal = true
-							default:
-								continue
-							}
-
-							// Check if it's actually an interface
-							isInterface := false
-							if !isExternal {
-								// Local type - check in file scope
-								if obj := file.Scope.Lookup(typeName); obj != nil && obj.Kind == ast.Typ {
-									if typeSpec, ok := obj.Decl.(*ast.TypeSpec); ok {
-										if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
-											isInterface = true
-										}
-									}
-								}
-							} else {
-								// External type - try to resolve it by parsing the external package
-								if selectorExpr, ok := starExpr.X.(*ast.SelectorExpr); ok {
-									if pkgIdent, ok := selectorExpr.X.(*ast.Ident); ok {
-										packagePath, exists := imports[pkgIdent.Name]
-										if exists {
-											isInterface = isExternalInterface(packagePath, typeName)
-										}
-									}
-								}
-							}
-
-							if isInterface {
-								pos := globalFileSet.Position(starExpr.Pos())
-								var paramName string
-								if len(param.Names) > 0 {
-									paramName = param.Names[0].Name
-								} else {
-									paramName = typeName
-								}
-								errors = append(errors, ValidationError{
-									Rule:     "R06-interface-usage",
-									File:     filePath,
-									Line:     pos.Line,
-									Column:   pos.Column,
-									Message:  fmt.Sprintf("Function parameter '%s' has type '*%s' - pointer to interface is an anti-pattern, use '%s' instead", paramName, typeName, typeName),
-									Severity: "error",
-								})
-							}
-						}
-					}
-				}
-
-				// Check return types - only flag if we can confirm it's actually an interface
-				if n.Type.Results != nil {
-					for _, result := range n.Type.Results.List {
-						if starExpr, ok := result.Type.(*ast.StarExpr); ok {
-							if ident, ok := starExpr.X.(*ast.Ident); ok {
-								// Look up the type to see if it's actually an interface
-								if obj := file.Scope.Lookup(ident.Name); obj != nil && obj.Kind == ast.Typ {
-									if typeSpec, ok := obj.Decl.(*ast.TypeSpec); ok {
-										if _, isInterface := typeSpec.Type.(*ast.InterfaceType); isInterface {
-											pos := globalFileSet.Position(starExpr.Pos())
-											errors = append(errors, ValidationError{
-												Rule:     "R06-interface-usage",
-												File:     filePath,
-												Line:     pos.Line,
-												Column:   pos.Column,
-												Message:  fmt.Sprintf("Function returns '*%s' - pointer to interface, use '%s' instead", ident.Name, ident.Name),
-												Severity: "error",
-											})
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-			return true
-		})
-	}
-
-	return errors
-}
-
-// isExternalInterface checks if a type in an external package is an interface
-func isExternalInterface(packagePath, typeName string) bool {
-	// Cache for parsed packages to avoid re-parsing
-	if externalPkg, exists := externalPackageCache[packagePath]; exists {
-		return checkTypeInPackage(externalPkg, typeName)
+	for _, err := range allErrors {
+		report.Summary[err.Severity]++
 	}
 
-	// Try to find the package in GOPATH/GOMODULE
-	pkgPath := strings.TrimPrefix(packagePath, "github.com/nex-prospect/nex-core-service/")
-
-	// Look for the package in current project first
-	localPath := "./" + pkgPath
-	if _, err := os.Stat(localPath); err == nil {
-		// Parse the local package
-		pkgFiles, err := filepath.Glob(filepath.Join(localPath, "*.go"))
-		if err != nil {
-			return false
-		}
-
-		fset := token.NewFileSet()
-		var files []*ast.File
-
-		for _, pkgFile := range pkgFiles {
-			// Skip test files
-			if strings.HasSuffix(pkgFile, "_test.go") {
-				continue
-			}
-
-			src, err := os.ReadFile(pkgFile)
-			if err != nil {
-				continue
-			}
-
-			file, err := parser.ParseFile(fset, pkgFile, src, parser.ParseComments)
-			if err != nil {
-				continue
-			}
-
-			files = append(files, file)
-		}
-
-		// Build package from files
-		if len(files) > 0 {
-			pkg := &ast.Package{
-				Name:  files[0].Name.Name,
-				Files: make(map[string]*ast.File),
-			}
-
-			for i, file := range files {
-				pkg.Files[pkgFiles[i]] = file
-			}
-
-			// Cache the package
-			if externalPackageCache == nil {
-				externalPackageCache = make(map[string]*ast.Package)
-			}
-			externalPackageCache[packagePath] = pkg
-
-			return checkTypeInPackage(pkg, typeName)
-		}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
 	}
 
-	return false
-}
-
-// checkTypeInPackage checks if a type name is an interface in the given package
-func checkTypeInPackage(pkg *ast.Package, typeName string) bool {
-	for _, file := range pkg.Files {
-		for _, decl := range file.Decls {
-			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
-				for _, spec := range genDecl.Specs {
-					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
-						if typeSpec.Name.Name == typeName {
-							_, isInterface := typeSpec.Type.(*ast.InterfaceType)
-							return isInterface
-						}
-					}
-				}
-			}
-		}
-	}
-	return false
+	return nil
 }
 
-// Cache for external packages to avoid re-parsing
-var externalPackageCache map[string]*ast.Package
-
-// loadGearConfig loads configuration from .gearrc file if it exists
-func loadGearConfig() (*GearConfig, error) {
-	config := &GearConfig{
-		Exclude: []string{},
-		Rules:   make(map[string]string),
+// printSummaryTable prints the number of findings per rule and severity
+func printSummaryTable(allErrors []lint.ValidationError) {
+	type ruleSeverity struct {
+		Rule     string
+		Severity string
 	}
 
-	// Check if .gearrc exists
-	if _, err := os.Stat(".gearrc"); os.IsNotExist(err) {
-		// No config file, return default config
-		return config, nil
+	counts := make(map[ruleSeverity]int)
+	var keys []ruleSeverity
+	for _, err := range allErrors {
+		key := ruleSeverity{Rule: err.Rule, Severity: err.Severity}
+		if counts[key] == 0 {
+			keys = append(keys, key)
+		}
+		counts[key]++
 	}
 
-	// Read the config file
-	data, err := os.ReadFile(".gearrc")
-	if err != nil {
-		return nil, fmt.Errorf("failed to read .gearrc: %w", err)
-	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Rule != keys[j].Rule {
+			return keys[i].Rule < keys[j].Rule
+		}
+		return keys[i].Severity < keys[j].Severity
+	})
 
-	// Parse YAML
-	if err := yaml.Unmarshal(data, config); err != nil {
-		return nil, fmt.Errorf("failed to parse .gearrc: %w", err)
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tCOUNT\tSEVERITY")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", key.Rule, counts[key], key.Severity)
 	}
-
-	return config, nil
+	w.Flush()
 }
 
 func init() {
 	validateCmd.Flags().StringSliceVarP(&excludeDirs, "exclude", "e", []string{}, "Comma-separated list of directories to exclude from validation")
+	validateCmd.Flags().BoolVar(&summaryTable, "summary-table", false, "Print a table of findings per rule and severity after the summary")
+	validateCmd.Flags().StringVar(&outputFormat, "format", "text", "Output format (text|json|sarif)")
+	validateCmd.Flags().StringVar(&diffBase, "diff", "", "Only report findings on lines changed since the merge base with this git ref")
+	validateCmd.Flags().BoolVar(&watchMode, "watch", false, "Re-run validation whenever a .go file changes")
+	validateCmd.Flags().BoolVar(&noFail, "no-fail", false, "Exit 0 whatever findings are reported, like --fail-on none")
+	validateCmd.Flags().StringVar(&failOn, "fail-on", "error", "Exit 1 when a finding has this severity or a higher one (error|warning|info|none)")
+	validateCmd.Flags().BoolVarP(&quietMode, "quiet", "q", false, "Only print findings and the summary, without progress output")
+	validateCmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-check every file instead of reusing the findings of unchanged files from .gear/cache.json")
+	validateCmd.Flags().StringVar(&configPath, "config", "", "Read the configuration from this file instead of .gearrc in the current directory")
+	validateCmd.Flags().StringSliceVar(&severityFlags, "severity-override", nil, "Set the severity of a rule for this run, e.g. R01=error, over .gearrc and the default (repeatable)")
+	validateCmd.Flags().BoolVar(&includeGenerated, "include-generated", false, "Also validate files marked \"Code generated ... DO NOT EDIT.\"")
 }
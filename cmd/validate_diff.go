@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+// lineRange is an inclusive range of changed lines in a file
+type lineRange struct {
+	Start int
+	End   int
+}
+
+// changedLines returns the lines changed in the working tree since the merge
+// base of baseRef and HEAD, keyed by path relative to the current directory.
+// Untracked files count as entirely changed and map to a nil range list.
+func changedLines(baseRef string) (map[string][]lineRange, error) {
+	mergeBase, err := gitOutput("merge-base", baseRef, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := gitOutput("diff", "--relative", "--unified=0", "--no-color", "--no-ext-diff", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string][]lineRange)
+	var current string
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			current = ""
+			if name, ok := strings.CutPrefix(line, "+++ b/"); ok {
+				current = filepath.Clean(name)
+			}
+		case strings.HasPrefix(line, "@@ ") && current != "":
+			if r, ok := parseHunkHeader(line); ok {
+				changed[current] = append(changed[current], r)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	untracked, err := gitOutput("ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(untracked, "\n") {
+		if name != "" {
+			changed[filepath.Clean(name)] = nil
+		}
+	}
+
+	return changed, nil
+}
+
+// parseHunkHeader reads the new-file range of "@@ -a,b +c,d @@"
+func parseHunkHeader(header string) (lineRange, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return lineRange{}, false
+	}
+
+	startText, countText, hasCount := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return lineRange{}, false
+	}
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return lineRange{}, false
+		}
+	}
+
+	// A pure deletion after line start affects the lines around it, e.g. a
+	// removed return makes the preceding response call a finding
+	if count == 0 {
+		return lineRange{Start: start, End: start + 1}, true
+	}
+	return lineRange{Start: start, End: start + count - 1}, true
+}
+
+// filterToDiff keeps the findings on changed lines. Findings without a line
+// (project-level checks) are kept when their file or directory changed.
+func filterToDiff(allErrors []lint.ValidationError, changed map[string][]lineRange) []lint.ValidationError {
+	var filtered []lint.ValidationError
+	for _, err := range allErrors {
+		file := filepath.Clean(err.File)
+		ranges, ok := changed[file]
+
+		if err.Line == 0 {
+			if ok || changedUnder(file, changed) {
+				filtered = append(filtered, err)
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if ranges == nil {
+			filtered = append(filtered, err)
+			continue
+		}
+		for _, r := range ranges {
+			if err.Line >= r.Start && err.Line <= r.End {
+				filtered = append(filtered, err)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func changedUnder(dir string, changed map[string][]lineRange) bool {
+	prefix := dir + string(filepath.Separator)
+	for file := range changed {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func gitOutput(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
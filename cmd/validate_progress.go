@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// progressMeter redraws a single stderr line with the share of files the
+// rules have checked. It only draws on a terminal, so piped output, CI logs
+// and JSON reports stay clean.
+type progressMeter struct {
+	enabled bool
+	done    int
+	total   int
+	shown   int // percentage on screen, -1 when the line is cleared
+}
+
+func newProgressMeter(total int) *progressMeter {
+	info, err := os.Stderr.Stat()
+	return &progressMeter{
+		enabled: !quietMode && total > 0 && err == nil && info.Mode()&os.ModeCharDevice != 0,
+		total:   total,
+		shown:   -1,
+	}
+}
+
+// advance records n more checked files, redrawing when the percentage changes
+func (p *progressMeter) advance(n int) {
+	p.done += n
+	percent := p.done * 100 / max(p.total, 1)
+	if !p.enabled || percent == p.shown {
+		return
+	}
+	p.shown = percent
+	fmt.Fprintf(os.Stderr, "\r⏳ Checked %d/%d files (%d%%)", p.done, p.total, percent)
+}
+
+// clear erases the progress line before other output is printed
+func (p *progressMeter) clear() {
+	if p.enabled && p.shown >= 0 {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+		p.shown = -1
+	}
+}
+
+// meterWriter clears the progress line before each write to w
+type meterWriter struct {
+	meter *progressMeter
+	w     io.Writer
+}
+
+func (m meterWriter) Write(p []byte) (int, error) {
+	m.meter.clear()
+	return m.w.Write(p)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/gomessguii/gear/pkg/lint"
+)
+
+// watchDebounce lets a burst of saves (editors, formatters) settle into one run
+const watchDebounce = 300 * time.Millisecond
+
+// watchProject validates the project, then again after every change to a
+// .go file outside the excluded paths, until interrupted
+func watchProject() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	// The first run loads the exclusions from .gearrc, so it comes before
+	// choosing the directories to watch
+	if err := runWatchedValidation(); err != nil {
+		return err
+	}
+	if err := watchDirs(watcher, "."); err != nil {
+		return err
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchDirs(watcher, event.Name); err != nil {
+						fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+					}
+					continue
+				}
+			}
+			if event.Op == fsnotify.Chmod || !strings.HasSuffix(event.Name, ".go") || lint.IsExcluded(event.Name, excludeDirs) {
+				continue
+			}
+			debounce = time.After(watchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  watch error: %v\n", err)
+
+		case <-debounce:
+			debounce = nil
+			if err := runWatchedValidation(); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			}
+
+		case <-interrupt:
+			fmt.Println("\n👋 Stopped watching")
+			return nil
+		}
+	}
+}
+
+// runWatchedValidation clears the screen and runs a fresh validation
+func runWatchedValidation() error {
+	if outputFormat == "text" {
+		fmt.Print("\033[H\033[2J")
+	}
+
+	if _, err := validateProject(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "\n👀 Watching for changes (%s, Ctrl-C to stop)...\n", time.Now().Format("15:04:05"))
+	return nil
+}
+
+// watchDirs adds root and its subdirectories to the watcher, skipping the
+// directories validation never reads
+func watchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		name := entry.Name()
+		if path != "." && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || lint.IsExcluded(path+"/", excludeDirs)) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"maps"
+	"os"
+	"path/filepath"
+)
+
+// CacheVersion is the format of Cache; caches of another version are discarded
+const CacheVersion = 1
+
+// Cache carries findings from one run to the next. Files are stamped with
+// their size and modification time: FileLocal rules only re-check files
+// whose stamp changed, the other rules look across files and re-check the
+// whole project. When no file changed, Reuse returns the previous findings
+// without parsing anything.
+//
+// A Cache is filled by RunRules, so it must not be shared by concurrent runs.
+type Cache struct {
+	Version  int                  `json:"version"`
+	Key      string               `json:"key"`
+	Files    map[string]FileStamp `json:"files"`
+	Findings []ValidationError    `json:"findings"`
+}
+
+// FileStamp identifies the version of a file
+type FileStamp struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mod_time"` // Unix nanoseconds
+}
+
+func stampOf(info os.FileInfo) FileStamp {
+	return FileStamp{Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+}
+
+// CacheKey digests what findings depend on besides the files: the options
+// of the run, including the IDs of the project's own rules, the project
+// go.mod and version, which identifies the rule implementations (e.g. the
+// gear version)
+func CacheKey(root string, opts Options, version string) string {
+	goMod, _ := os.ReadFile(filepath.Join(root, "go.mod"))
+	rules := make([]string, 0, len(opts.Rules))
+	for _, rule := range opts.Rules {
+		rules = append(rules, RuleID(rule.Name))
+	}
+	settings, _ := json.Marshal(struct {
+		Exclude          []string
+		Severities       map[string]string
+		AllowList        AllowList
+		Naming           Naming
+		DocComments      DocComments
+		Rules            []string
+		IncludeGenerated bool
+		Version          string
+		GoMod            string
+	}{opts.Exclude, opts.Severities, opts.AllowList, opts.Naming, opts.DocComments, rules, opts.IncludeGenerated, version, string(goMod)})
+
+	sum := sha256.Sum256(settings)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewCache returns an empty cache for runs with the key
+func NewCache(key string) *Cache {
+	return &Cache{Version: CacheVersion, Key: key}
+}
+
+// LoadCache reads the cache at path, returning an empty one when it is
+// missing, unreadable or was written with another key
+func LoadCache(path, key string) *Cache {
+	cache := NewCache(key)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	var previous Cache
+	if err := json.Unmarshal(data, &previous); err != nil || previous.Version != CacheVersion || previous.Key != key {
+		return cache
+	}
+	cache.Files = previous.Files
+	cache.Findings = previous.Findings
+	return cache
+}
+
+// Save writes the cache to path, creating its directory
+func (c *Cache) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Reuse returns the cached findings when the stamps match those of the
+// cached run, i.e. no file was changed, added or removed since
+func (c *Cache) Reuse(stamps map[string]FileStamp) ([]ValidationError, bool) {
+	if c.Files == nil || !maps.Equal(c.Files, stamps) {
+		return nil, false
+	}
+	return c.Findings, true
+}
+
+// StampFiles stamps the Go files ParseProject would parse, without parsing them
+func StampFiles(root string, opts Options) (map[string]FileStamp, error) {
+	stamps := make(map[string]FileStamp)
+	err := walkGoFiles(root, opts, func(path, fullPath string, info os.FileInfo) error {
+		stamps[path] = stampOf(info)
+		return nil
+	})
+	return stamps, err
+}
+
+// fresh reports whether path has the stamp it had in the cached run
+func (c *Cache) fresh(path string, stamps map[string]FileStamp) bool {
+	cached, ok := c.Files[path]
+	return ok && cached == stamps[path]
+}
+
+// stale returns the files of pkg that changed since the cached run
+func (c *Cache) stale(pkg *ast.Package, stamps map[string]FileStamp) *ast.Package {
+	changed := &ast.Package{Name: pkg.Name, Files: make(map[string]*ast.File)}
+	for path, file := range pkg.Files {
+		if !c.fresh(path, stamps) {
+			changed.Files[path] = file
+		}
+	}
+	return changed
+}
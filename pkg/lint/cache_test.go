@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeProject writes files, keyed by their path relative to root
+func writeProject(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// cachedRun validates root the way gear validate does, reusing the
+// findings cached at cachePath when nothing changed since the last run
+func cachedRun(t *testing.T, root, cachePath string, opts Options) []ValidationError {
+	t.Helper()
+	cache := LoadCache(cachePath, CacheKey(root, opts, "test"))
+	stamps, err := StampFiles(root, opts)
+	if err != nil {
+		t.Fatalf("StampFiles: %v", err)
+	}
+	if findings, ok := cache.Reuse(stamps); ok {
+		return findings
+	}
+
+	project, err := ParseProject(root, opts)
+	if err != nil {
+		t.Fatalf("ParseProject: %v", err)
+	}
+	opts.Cache = cache
+	rules := append(EnabledRules(opts.Severities), opts.Rules...)
+	findings := RunRules(rules, project, opts)
+	if err := cache.Save(cachePath); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return findings
+}
+
+func countRule(findings []ValidationError, rule string) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Rule == rule {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCachedRunAfterNamingChange(t *testing.T) {
+	root := t.TempDir()
+	writeProject(t, root, map[string]string{
+		"go.mod": "module example.com/demo\n\ngo 1.22\n",
+		"pkg/user/service/user_service.go": `package service
+
+type IUserService interface {
+	Get() error
+}
+`,
+	})
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	if got := countRule(cachedRun(t, root, cachePath, Options{}), "R12-file-naming"); got != 1 {
+		t.Fatalf("default naming: %d R12 findings, want 1", got)
+	}
+	renamed := Options{Naming: Naming{Interface: "I{Domain}{Layer}"}}
+	if got := countRule(cachedRun(t, root, cachePath, renamed), "R12-file-naming"); got != 0 {
+		t.Fatalf("I{Domain}{Layer} naming: %d R12 findings, want 0 - the cache replayed the previous run", got)
+	}
+}
+
+func TestCachedRunAfterAddingRule(t *testing.T) {
+	root := t.TempDir()
+	writeProject(t, root, map[string]string{
+		"go.mod":           "module example.com/demo\n\ngo 1.22\n",
+		"pkg/user/user.go": "package user\n",
+	})
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	custom := ValidationRule{
+		Name:        "C01-always",
+		Description: "Always: reports every package",
+		Check: func(p *Project, pkg *ast.Package) []ValidationError {
+			return []ValidationError{{Rule: "C01-always", Message: "checked", Severity: "warning"}}
+		},
+	}
+
+	if got := countRule(cachedRun(t, root, cachePath, Options{}), "C01-always"); got != 0 {
+		t.Fatalf("without the rule: %d C01 findings, want 0", got)
+	}
+	withRule := Options{Rules: []ValidationRule{custom}}
+	if got := countRule(cachedRun(t, root, cachePath, withRule), "C01-always"); got == 0 {
+		t.Fatal("with the rule: no C01 findings - the cache replayed the previous run")
+	}
+}
@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigVersion is the .gearrc format this gear writes and understands.
+// Files without a version field are version 0 and migrated on load.
+const ConfigVersion = 1
+
+// Config represents the .gearrc configuration file
+type Config struct {
+	Version   int               `yaml:"version"`
+	Exclude   []string          `yaml:"exclude"`
+	Rules     map[string]string `yaml:"rules,omitempty"`
+	AllowList AllowList         `yaml:"allow_list,omitempty"`
+	Naming    Naming            `yaml:"naming,omitempty"`
+	// DocComments scopes R22, which only runs when rules gives it a severity
+	DocComments DocComments `yaml:"doc_comments,omitempty"`
+}
+
+// AllowList extends the built-in data-struct naming conventions that R01
+// exempts from the unexported-struct requirement, and lists the string
+// values R21 must not report as secrets
+type AllowList struct {
+	Suffixes []string `yaml:"suffixes,omitempty"`
+	Prefixes []string `yaml:"prefixes,omitempty"`
+	// Secrets are regular expressions matched against string literals,
+	// such as test fixtures or public keys R21 would otherwise report
+	Secrets []string `yaml:"secrets,omitempty"`
+}
+
+// Validate checks that the secret patterns are valid regular expressions
+func (a AllowList) Validate() error {
+	for _, pattern := range a.Secrets {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("allow_list: invalid secrets pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// DocComments limits R22 to some layers of the domain packages
+type DocComments struct {
+	// Layers lists the layers R22 checks, e.g. [service, repository]; empty
+	// checks them all
+	Layers []string `yaml:"layers,omitempty"`
+}
+
+// Validate checks that the layers are GEAR layers
+func (d DocComments) Validate() error {
+	for _, layer := range d.Layers {
+		if !slices.Contains(LayerNames, layer) {
+			return fmt.Errorf("doc_comments: unknown layer %q (expected %s)", layer, strings.Join(LayerNames, ", "))
+		}
+	}
+	return nil
+}
+
+// covers reports whether R22 checks the layer; declarations outside any
+// layer are only checked when no layer is listed
+func (d DocComments) covers(layer string) bool {
+	return len(d.Layers) == 0 || slices.Contains(d.Layers, layer)
+}
+
+// Options returns the validation options set by the config
+func (c *Config) Options() Options {
+	return Options{
+		Exclude:     c.Exclude,
+		Severities:  c.Rules,
+		AllowList:   c.AllowList,
+		Naming:      c.Naming,
+		DocComments: c.DocComments,
+	}
+}
+
+// LoadConfig loads the .gearrc file of the project at root, returning the
+// default config when there is none
+func LoadConfig(root string) (*Config, error) {
+	path := filepath.Join(root, ".gearrc")
+
+	// Check if .gearrc exists
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// No config file, return default config
+		return &Config{
+			Exclude: []string{},
+			Rules:   make(map[string]string),
+		}, nil
+	}
+
+	return LoadConfigFile(path)
+}
+
+// LoadConfigFile loads the config at path, a file in the .gearrc format.
+// Unlike LoadConfig it fails when the file does not exist.
+func LoadConfigFile(path string) (*Config, error) {
+	config := &Config{
+		Exclude: []string{},
+		Rules:   make(map[string]string),
+	}
+
+	// Read the config file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// Parse YAML
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	switch {
+	case config.Version < 0:
+		return nil, fmt.Errorf("%s: invalid version %d", path, config.Version)
+	case config.Version > ConfigVersion:
+		fmt.Fprintf(os.Stderr, "⚠️  %s is version %d but this gear understands up to version %d - upgrade gear, unknown settings are ignored\n", path, config.Version, ConfigVersion)
+	case config.Version < ConfigVersion:
+		migrateConfig(config)
+	}
+
+	if err := config.Naming.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.AllowList.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.DocComments.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// migrateConfig upgrades a config read from an older .gearrc format to
+// ConfigVersion, one version at a time
+func migrateConfig(config *Config) {
+	if config.Version == 0 {
+		// Unversioned files share the version 1 layout
+		config.Version = 1
+	}
+}
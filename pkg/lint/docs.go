@@ -0,0 +1,306 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+)
+
+// ruleDocsBaseURL is where the rule reference is published
+const ruleDocsBaseURL = "https://github.com/gomessguii/gear/blob/main/docs/rules.md"
+
+// RuleDoc documents a validation rule; it backs `gear explain` and the
+// documentation links attached to findings
+type RuleDoc struct {
+	ID              string
+	Name            string
+	DefaultSeverity string
+	Summary         string
+	Rationale       string
+	Example         string
+}
+
+// DocURL returns the link to the rule's section of the rule reference
+func (r RuleDoc) DocURL() string {
+	return ruleDocsBaseURL + "#" + strings.ToLower(r.ID+"-"+strings.ReplaceAll(r.Name, " ", "-"))
+}
+
+var ruleRegistry = []RuleDoc{
+	{
+		ID:              "R01",
+		Name:            "Interface contracts",
+		DefaultSeverity: "warning",
+		Summary:         "Types with behaviour are exposed as exported interfaces implemented by unexported structs.",
+		Rationale: `Callers depend on the interface, so the implementation can change or be
+replaced by a test double without touching them. Data structs (models,
+requests, responses, configs, ...) are exempt.`,
+		Example: `type UserService interface { GetUser(ctx context.Context, id uuid.UUID) (*model.User, error) }
+type userService struct { repo repository.UserRepository }`,
+	},
+	{
+		ID:              "R02",
+		Name:            "Interface usage",
+		DefaultSeverity: "error",
+		Summary:         "Interfaces are passed by value, never as pointers.",
+		Rationale: `An interface value already holds a pointer to its implementation. A
+*Interface adds indirection, does not satisfy the interface itself and is
+almost always a mistake.`,
+		Example: `func NewUserHandler(svc service.UserService) UserHandler   // not *service.UserService`,
+	},
+	{
+		ID:              "R03",
+		Name:            "Constructor patterns",
+		DefaultSeverity: "warning",
+		Summary:         "New* constructors return the interface, not a pointer to the struct.",
+		Rationale: `Returning the interface keeps the concrete struct unexported and forces
+callers to program against the contract.`,
+		Example: `func NewUserService(repo repository.UserRepository) UserService {
+	return &userService{repo: repo}
+}`,
+	},
+	{
+		ID:              "R04",
+		Name:            "Domain boundaries",
+		DefaultSeverity: "info",
+		Summary:         "Domains keep the handler, service, repository and model layers separate.",
+		Rationale: `Each layer has one responsibility: transport, business logic, persistence
+and data. Keeping them in separate packages makes dependencies explicit.`,
+		Example: `pkg/user/{handler,service,repository,model}`,
+	},
+	{
+		ID:              "R05",
+		Name:            "Centralized configuration",
+		DefaultSeverity: "error",
+		Summary:         "Configuration is loaded in one internal/config package.",
+		Rationale: `Reading environment variables in one place documents every setting,
+validates them together and keeps os.Getenv out of business code.`,
+		Example: `cfg, err := config.LoadConfig()`,
+	},
+	{
+		ID:              "R06",
+		Name:            "Systematic error handling",
+		DefaultSeverity: "error",
+		Summary:         "Errors are built from one internal/errors package with stable codes.",
+		Rationale: `Shared error codes give clients a consistent error format and let
+handlers map codes to HTTP statuses in one place.`,
+		Example: `return nil, errors.ErrNotFoundInstance.WithError(err)`,
+	},
+	{
+		ID:              "R07",
+		Name:            "Error mapping",
+		DefaultSeverity: "warning",
+		Summary:         "Not-found errors are not wrapped as internal errors.",
+		Rationale: `Wrapping gorm.ErrRecordNotFound in ErrInternalInstance turns a missing
+record into an HTTP 500 instead of a 404.`,
+		Example: `if stderrors.Is(err, gorm.ErrRecordNotFound) {
+	return nil, errors.ErrNotFoundInstance.WithError(err)
+}`,
+	},
+	{
+		ID:              "R08",
+		Name:            "Domain independence",
+		DefaultSeverity: "warning",
+		Summary:         "Domains do not import each other in a cycle.",
+		Rationale: `A cycle between domains means neither can change or be extracted on its
+own. Break it with an interface owned by one side or a shared package.`,
+		Example: `pkg/order -> pkg/user is fine; pkg/user -> pkg/order -> pkg/user is not`,
+	},
+	{
+		ID:              "R09",
+		Name:            "Early return",
+		DefaultSeverity: "warning",
+		Summary:         "Handlers return right after writing an error response.",
+		Rationale: `Without a return the handler keeps running after the error response,
+usually writing a second response or using a nil result.`,
+		Example: `if err != nil {
+	httputil.RespondError(c, err)
+	return
+}`,
+	},
+	{
+		ID:              "R10",
+		Name:            "Interface placement",
+		DefaultSeverity: "off",
+		Summary:         "Interfaces are declared by their consumer, not next to their only implementation.",
+		Rationale: `Go idiom defines small interfaces where they are used. This conflicts
+with GEAR's interface-first layers, so the rule is opt-in.`,
+		Example: `// pkg/user/service declares the UserRepository it needs
+type UserRepository interface { GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) }`,
+	},
+	{
+		ID:              "R11",
+		Name:            "Model leakage",
+		DefaultSeverity: "warning",
+		Summary:         "Handlers serialize the model's Response, not the model.",
+		Rationale: `Models hide internal fields with json:"-" and the Response decides what
+clients see. Serializing the model skips that contract and couples the API
+to the storage schema.`,
+		Example: `httputil.RespondJSON(c, http.StatusOK, user.ToResponse())   // not user`,
+	},
+	{
+		ID:              "R12",
+		Name:            "File naming",
+		DefaultSeverity: "info",
+		Summary:         "Layer files declare a type named after the file.",
+		Rationale: `Generated files are named after their primary type. When a type is
+renamed without its file, the layout stops telling readers where code lives.`,
+		Example: `pkg/user/service/user_service.go   // declares UserService`,
+	},
+	{
+		ID:              "R13",
+		Name:            "Bind errors",
+		DefaultSeverity: "warning",
+		Summary:         "Handlers check the error of ShouldBindJSON and friends before using the bound value.",
+		Rationale: `A failed bind leaves the request partially decoded. Ignoring the error
+lets malformed input reach the service as zero values instead of a 400.`,
+		Example: `if err := c.ShouldBindJSON(&request); err != nil {
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	return
+}`,
+	},
+	{
+		ID:              "R14",
+		Name:            "Repository interface",
+		DefaultSeverity: "warning",
+		Summary:         "Every repository package exports an XRepository interface and keeps its structs unexported.",
+		Rationale: `Services depend on the repository contract, not on gorm. Without the
+interface they import the concrete struct and can no longer be tested with
+a fake or wrapped in a decorator such as a cache.`,
+		Example: `type UserRepository interface { GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) }
+type userRepository struct { db *gorm.DB }`,
+	},
+	{
+		ID:              "R15",
+		Name:            "Receiver names",
+		DefaultSeverity: "off",
+		Summary:         "Every method of a type uses the same receiver name.",
+		Rationale: `Go convention gives a type one short receiver name. Hand-edited code
+drifting to this, self or the full type name makes methods of the same type
+read differently. It is a style rule, so it is opt-in.`,
+		Example: `func (s *userService) GetUser(...)     // and s in every other method,
+func (s *userService) CreateUser(...)  // not this or userService`,
+	},
+	{
+		ID:              "R16",
+		Name:            "No panics",
+		DefaultSeverity: "warning",
+		Summary:         "Services, repositories and handlers return errors instead of calling panic, log.Fatal or os.Exit.",
+		Rationale: `A panic in a request path drops the connection or, outside gin's recovery,
+kills the whole server; log.Fatal and os.Exit always do. Failures belong in
+an error from the errors package so the handler can answer with a status.
+main and cmd are not layers and may still exit on startup errors.`,
+		Example: `if err != nil {
+	return nil, errors.ErrInternalInstance.WithError(err) // not panic(err)
+}`,
+	},
+	{
+		ID:              "R17",
+		Name:            "Context propagation",
+		DefaultSeverity: "warning",
+		Summary:         "Handlers and services pass the request context down instead of calling context.Background or context.TODO.",
+		Rationale: `A fresh root context mid-request is not cancelled when the client goes
+away or the request times out, and loses the trace and values of the
+request. Tests, init functions, New* constructors and package-level
+initializers run outside requests and may still create one.`,
+		Example: `user, err := h.userService.GetUser(c.Request.Context(), id) // not context.Background()`,
+	},
+	{
+		ID:              "R18",
+		Name:            "Single service",
+		DefaultSeverity: "warning",
+		Summary:         "A handler struct holds the service of its own domain only.",
+		Rationale: `A handler injected with the services of several domains orchestrates them
+itself, which is business logic in the transport layer. Its own service
+should call the other domains, or the handler should be split. Aggregating
+endpoints are a legitimate exception, hence a warning.`,
+		Example: `type orderHandler struct {
+	orderService service.OrderService // not also userservice.UserService
+}`,
+	},
+	{
+		ID:              "R19",
+		Name:            "Layer direction",
+		DefaultSeverity: "warning",
+		Summary:         "Imports point handler -> service -> repository -> model, never back.",
+		Rationale: `A service importing a handler, or a repository importing a service, ties
+the lower layer to the one built on it: it can no longer be reused or
+tested without it, and the two risk an import cycle. Shared types belong
+in the lower layer, usually the model.`,
+		Example: `// pkg/user/service/user_service.go
+import "example.com/app/pkg/user/model" // not ".../pkg/user/handler"`,
+	},
+	{
+		ID:              "R20",
+		Name:            "Rows close",
+		DefaultSeverity: "warning",
+		Summary:         "Rows returned by Query or Queryx are closed with a defer.",
+		Rationale: `Rows hold a connection of the pool until they are closed or fully read.
+A repository forgetting rows.Close(), or calling it after code that can
+return early, leaks the connection on that path, and the pool runs dry
+under load. Deferring Close right after checking the error covers every
+path.`,
+		Example: `rows, err := r.db.QueryxContext(ctx, query, args...)
+if err != nil {
+	return nil, err
+}
+defer rows.Close()`,
+	},
+	{
+		ID:              "R21",
+		Name:            "Hardcoded secrets",
+		DefaultSeverity: "off",
+		Summary:         "String literals do not hold credentials: keys, tokens or connection strings with a password.",
+		Rationale: `A secret committed with the code is readable by everyone with access to
+the repository and its history, and rotating it means a release. Secrets
+belong in the environment, read through internal/config. The rule matches
+known formats and random-looking tokens, so it has false positives; list
+them in allow_list.secrets of .gearrc. It is opt-in.`,
+		Example: `apiKey := cfg.PaymentsAPIKey // not "sk_live_..." in the source`,
+	},
+	{
+		ID:              "R22",
+		Name:            "Doc comments",
+		DefaultSeverity: "off",
+		Summary:         "Exported functions, types and methods of the domains have a doc comment starting with their name.",
+		Rationale: `The exported API of a domain is the contract other layers and domains
+program against, and its doc comments are what go doc and editors show
+for it. Starting the comment with the name, as Go convention has it,
+keeps it findable. add-domain documents what it generates; the rule keeps
+hand-written additions consistent. doc_comments.layers in .gearrc limits
+it to some layers. It is opt-in.`,
+		Example: `// Archive hides the user from lists without deleting it
+func (u *User) Archive()`,
+	},
+	{
+		ID:              "R23",
+		Name:            "Body decoding",
+		DefaultSeverity: "off",
+		Summary:         "Handlers decode JSON request bodies with httputil.DecodeJSON, not gin's ShouldBindJSON or a bare json.Decoder.",
+		Rationale: `ShouldBindJSON decodes an empty body into a zero value without an
+error, so a Create sent without data goes on to the service, and a bare
+json.Decoder answers an empty body like a malformed one. DecodeJSON,
+generated by add-domain, rejects a missing body and names the rule each
+failure broke (required, json or type), leaving missing fields to the
+model's Validate. It is opt-in.`,
+		Example: `var request model.UserRequest
+if err := httputil.DecodeJSON(c.Request, &request); err != nil {
+	httputil.RespondError(c, err)
+	return
+}`,
+	},
+}
+
+// LookupRule finds the documentation of a rule by ID ("R01") or full name ("R01-interface-contracts")
+func LookupRule(id string) (RuleDoc, bool) {
+	id = strings.ToUpper(RuleID(id))
+	for _, rule := range ruleRegistry {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return RuleDoc{}, false
+}
+
+// RuleDocs returns the documentation of every rule in ID order
+func RuleDocs() []RuleDoc {
+	return slices.Clone(ruleRegistry)
+}
@@ -0,0 +1,235 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isExternalInterface checks if a type in an external package is an interface
+func (p *Project) isExternalInterface(packagePath, typeName string) bool {
+	dir, ok := p.resolvePackageDir(packagePath)
+	if !ok {
+		return false
+	}
+
+	// Cache for parsed packages to avoid re-parsing, keyed by resolved location
+	if externalPkg, exists := p.externalPackages[dir]; exists {
+		return checkTypeInPackage(externalPkg, typeName)
+	}
+
+	pkgFiles, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return false
+	}
+
+	fset := token.NewFileSet()
+	pkg := &ast.Package{Files: make(map[string]*ast.File)}
+
+	for _, pkgFile := range pkgFiles {
+		// Skip test files
+		if strings.HasSuffix(pkgFile, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(pkgFile)
+		if err != nil {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, pkgFile, src, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		pkg.Name = file.Name.Name
+		pkg.Files[pkgFile] = file
+	}
+
+	if len(pkg.Files) == 0 {
+		return false
+	}
+
+	if p.externalPackages == nil {
+		p.externalPackages = make(map[string]*ast.Package)
+	}
+	p.externalPackages[dir] = pkg
+
+	return checkTypeInPackage(pkg, typeName)
+}
+
+// resolvePackageDir finds the source directory of an import path: the current
+// module first, then vendor/ when the project vendors its dependencies, then
+// the module cache at the version required in go.mod
+func (p *Project) resolvePackageDir(importPath string) (string, bool) {
+	if moduleName := p.modulePath(); moduleName != "" {
+		if importPath == moduleName {
+			return p.Root, true
+		}
+		if rest, ok := strings.CutPrefix(importPath, moduleName+"/"); ok {
+			return isDir(filepath.Join(p.Root, filepath.FromSlash(rest)))
+		}
+	}
+
+	if p.usesVendor() {
+		return isDir(filepath.Join(p.Root, "vendor", filepath.FromSlash(importPath)))
+	}
+
+	modulePath, version := p.requiredModule(importPath)
+	if modulePath == "" {
+		return "", false
+	}
+
+	escaped, err := escapeModulePath(modulePath)
+	if err != nil {
+		return "", false
+	}
+	dir := filepath.Join(moduleCacheDir(), filepath.FromSlash(escaped)+"@"+version)
+	if rest := strings.TrimPrefix(importPath, modulePath); rest != "" {
+		dir = filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(rest, "/")))
+	}
+	return isDir(dir)
+}
+
+func isDir(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// usesVendor reports whether the go command builds the project from vendor/,
+// which it does by default when vendor/modules.txt exists unless GOFLAGS says otherwise
+func (p *Project) usesVendor() bool {
+	for _, flag := range strings.Fields(os.Getenv("GOFLAGS")) {
+		switch flag {
+		case "-mod=vendor":
+			return true
+		case "-mod=mod", "-mod=readonly":
+			return false
+		}
+	}
+	_, err := os.Stat(filepath.Join(p.Root, "vendor", "modules.txt"))
+	return err == nil
+}
+
+// requiredModule returns the go.mod requirement providing an import path
+func (p *Project) requiredModule(importPath string) (string, string) {
+	if p.goModRequirements == nil {
+		p.goModRequirements = p.readGoModRequirements()
+	}
+
+	bestPath, bestVersion := "", ""
+	for modulePath, version := range p.goModRequirements {
+		if importPath != modulePath && !strings.HasPrefix(importPath, modulePath+"/") {
+			continue
+		}
+		if len(modulePath) > len(bestPath) {
+			bestPath, bestVersion = modulePath, version
+		}
+	}
+	return bestPath, bestVersion
+}
+
+// modulePath returns the module path declared in go.mod, or "" without one
+func (p *Project) modulePath() string {
+	data, err := os.ReadFile(filepath.Join(p.Root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`)
+		}
+	}
+	return ""
+}
+
+// readGoModRequirements parses the require directives of go.mod
+func (p *Project) readGoModRequirements() map[string]string {
+	requirements := make(map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(p.Root, "go.mod"))
+	if err != nil {
+		return requirements
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inBlock:
+			continue
+		}
+
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			requirements[fields[0]] = fields[1]
+		}
+	}
+	return requirements
+}
+
+// moduleCacheDir returns GOMODCACHE, defaulting to $GOPATH/pkg/mod like the go command
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, _ := os.UserHomeDir()
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// escapeModulePath applies the module cache case encoding ("!" before lowercased capitals)
+func escapeModulePath(modulePath string) (string, error) {
+	var b strings.Builder
+	for _, r := range modulePath {
+		if r == '!' {
+			return "", fmt.Errorf("invalid module path %q", modulePath)
+		}
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), nil
+}
+
+// checkTypeInPackage checks if a type name is an interface in the given package
+func checkTypeInPackage(pkg *ast.Package, typeName string) bool {
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+				for _, spec := range genDecl.Specs {
+					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+						if typeSpec.Name.Name == typeName {
+							_, isInterface := typeSpec.Type.(*ast.InterfaceType)
+							return isInterface
+						}
+					}
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,483 @@
+// Package lint checks Go projects against the GEAR architecture rules. It
+// is the engine behind gear validate, usable from tests and other tools:
+//
+//	config, err := lint.LoadConfig(".")
+//	...
+//	findings, err := lint.Validate(".", config.Options())
+//
+// Validate is ParseProject followed by RunRules with the EnabledRules, for
+// callers that inspect the parsed Project themselves. Projects check rules
+// of their own by passing them in Options.Rules. The package keeps no
+// global state, so projects can be validated concurrently.
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ValidationRule is a GEAR rule: Check runs per package, ProjectCheck once
+// over the whole project
+type ValidationRule struct {
+	Name        string
+	Description string
+	Check       func(p *Project, pkg *ast.Package) []ValidationError
+	// ProjectCheck runs once over every parsed package, for rules that need
+	// a whole-project view. When set, Check is ignored.
+	ProjectCheck func(p *Project) []ValidationError
+	// FileLocal marks Check rules whose findings in a file only depend on
+	// that file, so a Cache can keep them per file
+	FileLocal bool
+	// Optional rules only run when .gearrc gives them a severity
+	Optional bool
+}
+
+// ValidationError is a finding of a rule at a position of the project
+type ValidationError struct {
+	Rule     string `json:"rule"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"` // "error", "warning", "info"
+	DocURL   string `json:"doc_url,omitempty"`
+}
+
+// Project is a parsed Go project, the input of the rules. It caches the
+// external packages the rules resolve, so it is not safe for concurrent use.
+type Project struct {
+	// Root is the project directory
+	Root string
+	// Fset holds the positions of the parsed files
+	Fset *token.FileSet
+	// Packages are the parsed files grouped by package name, keyed by their
+	// path relative to Root
+	Packages map[string]*ast.Package
+	// Stamps are the size and modification time of the files walked,
+	// generated files included
+	Stamps map[string]FileStamp
+	// AllowList extends the data-struct naming conventions exempt from R01
+	// and the secret-like values R21 accepts
+	AllowList AllowList
+	// Naming derives the layer type names the naming checks expect
+	Naming Naming
+	// DocComments limits R22 to some layers
+	DocComments DocComments
+
+	externalPackages  map[string]*ast.Package // keyed by package directory
+	goModRequirements map[string]string       // module path -> version, loaded on first use
+}
+
+// Options configures a validation run
+type Options struct {
+	// Exclude lists file names, directories and globs left out of the run
+	Exclude []string
+	// Severities overrides the severity of rules by ID ("R01"); "off"
+	// disables a rule and optional rules only run when given one
+	Severities map[string]string
+	// AllowList extends the data-struct naming conventions exempt from R01
+	// and the secret-like values R21 accepts
+	AllowList AllowList
+	// Naming sets the templates of the layer type names (R03, R12, R14, R17, R18)
+	Naming Naming
+	// DocComments limits the doc comment check (R22) to some layers
+	DocComments DocComments
+	// Rules are the project's own rules, such as those of gear add-rule,
+	// checked after the GEAR rules. Severities apply to them by ID too.
+	Rules []ValidationRule
+	// IncludeGenerated also checks files marked "Code generated ... DO NOT EDIT."
+	IncludeGenerated bool
+	// Progress receives a line before each rule runs; nil discards them
+	Progress io.Writer
+	// Checked, when set, is called with the number of files each check covered
+	Checked func(files int)
+	// Cache, when set, supplies the findings of FileLocal rules on files
+	// whose stamp did not change, and receives the findings of the run
+	Cache *Cache
+}
+
+// Validate parses the Go project at root and checks it against the rules
+// enabled by opts. Findings name files relative to root.
+func Validate(root string, opts Options) ([]ValidationError, error) {
+	if opts.Cache != nil {
+		stamps, err := StampFiles(root, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat project: %w", err)
+		}
+		if findings, ok := opts.Cache.Reuse(stamps); ok {
+			return findings, nil
+		}
+	}
+
+	project, err := ParseProject(root, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse project: %w", err)
+	}
+	rules := append(EnabledRules(opts.Severities), enabledRules(opts.Rules, opts.Severities)...)
+	return RunRules(rules, project, opts), nil
+}
+
+// Rules lists every GEAR rule in ID order
+func Rules() []ValidationRule {
+	return []ValidationRule{
+		{
+			Name:        "R01-interface-contracts",
+			Description: "Interface contracts: exported interfaces + unexported structs",
+			Check:       validateInterfaceContracts,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R02-interface-usage",
+			Description: "Interface usage: no pointer-to-interface anti-patterns",
+			Check:       validateInterfaceUsage,
+		},
+		{
+			Name:        "R03-constructor-patterns",
+			Description: "Constructor patterns: constructors return interfaces",
+			Check:       validateConstructorPatterns,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R04-domain-boundaries",
+			Description: "Domain boundaries: clean layer separation",
+			Check:       validateDomainBoundaries,
+			FileLocal:   true,
+		},
+		{
+			Name:         "R05-centralized-config",
+			Description:  "Centralized configuration: internal/config package exists",
+			ProjectCheck: validateCentralizedConfig,
+		},
+		{
+			Name:         "R06-systematic-errors",
+			Description:  "Systematic error handling: internal/errors package exists",
+			ProjectCheck: validateSystematicErrors,
+		},
+		{
+			Name:        "R07-error-mapping",
+			Description: "Error mapping: not-found errors are not wrapped as internal",
+			Check:       validateErrorMapping,
+			FileLocal:   true,
+		},
+		{
+			Name:         "R08-domain-cycles",
+			Description:  "Domain independence: no import cycles between domains",
+			ProjectCheck: validateDomainCycles,
+		},
+		{
+			Name:        "R09-early-return",
+			Description: "Early return: handlers return after writing an error response",
+			Check:       validateEarlyReturn,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R10-interface-placement",
+			Description: "Interface placement: interfaces are not declared next to their only implementation",
+			Check:       validateInterfacePlacement,
+			Optional:    true,
+		},
+		{
+			Name:         "R11-model-leakage",
+			Description:  "Model leakage: handlers serialize responses, not models",
+			ProjectCheck: validateModelLeakage,
+		},
+		{
+			Name:        "R12-file-naming",
+			Description: "File naming: layer files are named after their primary type",
+			Check:       validateFileNaming,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R13-bind-errors",
+			Description: "Bind errors: request binding errors are checked",
+			Check:       validateBindErrors,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R14-repository-interface",
+			Description: "Repository interface: repositories are exposed through an exported interface",
+			Check:       validateRepositoryInterfaces,
+		},
+		{
+			Name:        "R15-receiver-names",
+			Description: "Receiver names: methods of a type use the same receiver name",
+			Check:       validateReceiverNames,
+			Optional:    true,
+		},
+		{
+			Name:        "R16-no-panics",
+			Description: "No panics: services, repositories and handlers return errors instead of crashing",
+			Check:       validatePanics,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R17-context-propagation",
+			Description: "Context propagation: handlers and services pass the request context down",
+			Check:       validateContextPropagation,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R18-single-service",
+			Description: "Single service: handlers depend on the service of one domain",
+			Check:       validateSingleService,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R19-layer-direction",
+			Description: "Layer direction: layers do not import the layers above them",
+			Check:       validateLayerDirection,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R20-rows-close",
+			Description: "Rows close: repositories defer closing the rows of their queries",
+			Check:       validateRowsClose,
+			FileLocal:   true,
+		},
+		{
+			Name:        "R21-hardcoded-secrets",
+			Description: "Hardcoded secrets: no credentials in string literals",
+			Check:       validateHardcodedSecrets,
+			FileLocal:   true,
+			Optional:    true,
+		},
+		{
+			Name:        "R22-doc-comments",
+			Description: "Doc comments: exported declarations of the domains are documented",
+			Check:       validateDocComments,
+			FileLocal:   true,
+			Optional:    true,
+		},
+		{
+			Name:        "R23-body-decoding",
+			Description: "Body decoding: handlers decode JSON bodies rejecting an empty one",
+			Check:       validateBodyDecoding,
+			FileLocal:   true,
+			Optional:    true,
+		},
+	}
+}
+
+// EnabledRules returns the rules the severities do not turn off, leaving out
+// optional rules they do not give a severity
+func EnabledRules(severities map[string]string) []ValidationRule {
+	return enabledRules(Rules(), severities)
+}
+
+func enabledRules(rules []ValidationRule, severities map[string]string) []ValidationRule {
+	var enabled []ValidationRule
+	for _, rule := range rules {
+		severity, configured := severities[RuleID(rule.Name)]
+		if severity == "off" || (rule.Optional && !configured) {
+			continue
+		}
+		enabled = append(enabled, rule)
+	}
+	return enabled
+}
+
+// RunRules checks the parsed project against the rules, applying the
+// severities of opts and linking the rule docs
+func RunRules(rules []ValidationRule, project *Project, opts Options) []ValidationError {
+	var allErrors []ValidationError
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
+	}
+	checked := opts.Checked
+	if checked == nil {
+		checked = func(int) {}
+	}
+
+	// Findings of file-local rules on unchanged files are taken from the cache
+	cached := make(map[string][]ValidationError)
+	if opts.Cache != nil {
+		for _, finding := range opts.Cache.Findings {
+			if opts.Cache.fresh(finding.File, project.Stamps) {
+				cached[finding.Rule] = append(cached[finding.Rule], finding)
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		fmt.Fprintf(progress, "  Checking %s...\n", rule.Description)
+		if rule.ProjectCheck != nil {
+			allErrors = append(allErrors, rule.ProjectCheck(project)...)
+			checked(project.FileCount())
+			continue
+		}
+		for _, pkg := range project.Packages {
+			checkedPkg := pkg
+			if rule.FileLocal && opts.Cache != nil {
+				checkedPkg = opts.Cache.stale(pkg, project.Stamps)
+			}
+			allErrors = append(allErrors, rule.Check(project, checkedPkg)...)
+			checked(len(pkg.Files))
+		}
+		if rule.FileLocal {
+			allErrors = append(allErrors, cached[rule.Name]...)
+		}
+	}
+
+	for i := range allErrors {
+		if severity, ok := opts.Severities[RuleID(allErrors[i].Rule)]; ok {
+			allErrors[i].Severity = severity
+		}
+		if doc, ok := LookupRule(allErrors[i].Rule); ok {
+			allErrors[i].DocURL = doc.DocURL()
+		}
+	}
+
+	if opts.Cache != nil {
+		opts.Cache.Files = project.Stamps
+		opts.Cache.Findings = allErrors
+	}
+
+	return allErrors
+}
+
+// RuleID returns the short ID of a rule name, e.g. "R01" for "R01-interface-contracts"
+func RuleID(name string) string {
+	id, _, _ := strings.Cut(name, "-")
+	return id
+}
+
+// IsExcluded reports whether a path matches one of the exclude patterns: a
+// file name, a directory or a glob
+func IsExcluded(path string, patterns []string) bool {
+	for _, excludePattern := range patterns {
+		excludePattern = strings.TrimSpace(excludePattern)
+		if excludePattern == "" {
+			continue
+		}
+
+		// 1. Exact file name match (e.g., "main.go")
+		if filepath.Base(path) == excludePattern {
+			return true
+		}
+
+		// 2. Directory path match (e.g., "vendor", "scripts")
+		if strings.Contains(path, excludePattern+"/") || strings.HasSuffix(path, "/"+excludePattern) {
+			return true
+		}
+
+		// 3. Glob pattern match (e.g., "*_test.go", "*.pb.go")
+		if strings.Contains(excludePattern, "*") || strings.Contains(excludePattern, "?") {
+			// Match against filename only
+			if matched, err := filepath.Match(excludePattern, filepath.Base(path)); err == nil && matched {
+				return true
+			}
+			// Match against relative path for patterns like "pkg/*_test.go"
+			if matched, err := filepath.Match(excludePattern, path); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseProject parses the Go files under root that opts does not exclude
+func ParseProject(root string, opts Options) (*Project, error) {
+	fset := token.NewFileSet()
+	packages := make(map[string]*ast.Package)
+	stamps := make(map[string]FileStamp)
+
+	err := walkGoFiles(root, opts, func(path, fullPath string, info os.FileInfo) error {
+		stamps[path] = stampOf(info)
+
+		// Parse the file
+		src, err := os.ReadFile(fullPath)
+		if err != nil {
+			return err
+		}
+
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		// Generated code ("// Code generated ... DO NOT EDIT.") is not held to GEAR rules
+		if !opts.IncludeGenerated && ast.IsGenerated(file) {
+			return nil
+		}
+
+		// Group by package
+		pkgName := file.Name.Name
+		if packages[pkgName] == nil {
+			packages[pkgName] = &ast.Package{
+				Name:  pkgName,
+				Files: make(map[string]*ast.File),
+			}
+		}
+		packages[pkgName].Files[path] = file
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Project{
+		Root:        root,
+		Fset:        fset,
+		Packages:    packages,
+		Stamps:      stamps,
+		AllowList:   opts.AllowList,
+		Naming:      opts.Naming,
+		DocComments: opts.DocComments,
+	}, nil
+}
+
+// walkGoFiles calls fn with the path relative to root of every Go file under
+// root that opts does not exclude
+func walkGoFiles(root string, opts Options, fn func(path, fullPath string, info os.FileInfo) error) error {
+	return filepath.Walk(root, func(fullPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		path, err := filepath.Rel(root, fullPath)
+		if err != nil {
+			return err
+		}
+
+		// Skip non-Go files and default excluded directories
+		if !strings.HasSuffix(path, ".go") ||
+			strings.Contains(path, "vendor/") ||
+			strings.Contains(path, ".git/") {
+			return nil
+		}
+
+		// Skip user-specified excluded paths and patterns
+		if IsExcluded(path, opts.Exclude) {
+			return nil
+		}
+
+		// If this is a directory that should be skipped entirely, skip it
+		if info.IsDir() {
+			for _, excludeDir := range opts.Exclude {
+				excludeDir = strings.TrimSpace(excludeDir)
+				if excludeDir != "" && strings.HasSuffix(path, excludeDir) {
+					return filepath.SkipDir
+				}
+			}
+		}
+
+		return fn(path, fullPath, info)
+	})
+}
+
+// FileCount returns the number of parsed files
+func (p *Project) FileCount() int {
+	count := 0
+	for _, pkg := range p.Packages {
+		count += len(pkg.Files)
+	}
+	return count
+}
@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// NamingLayers are the layers whose interface, struct and constructor names
+// Naming derives
+var NamingLayers = []string{"handler", "service", "repository"}
+
+// Naming holds the templates the names of a domain's layer types are derived
+// from, set under naming in .gearrc. In a template {Domain} is the exported
+// domain name (User), {domain} the domain name itself (user) and {Layer} the
+// layer's suffix (Service), which Suffixes renames per layer. Empty templates
+// keep gear's names: UserService, userService and NewUserService.
+type Naming struct {
+	Interface   string            `yaml:"interface,omitempty"`
+	Struct      string            `yaml:"struct,omitempty"`
+	Constructor string            `yaml:"constructor,omitempty"`
+	Suffixes    map[string]string `yaml:"suffixes,omitempty"`
+}
+
+const (
+	defaultInterfaceTemplate   = "{Domain}{Layer}"
+	defaultStructTemplate      = "{domain}{Layer}"
+	defaultConstructorTemplate = "New{Domain}{Layer}"
+)
+
+// IsDefault reports whether the naming derives gear's own names
+func (n Naming) IsDefault() bool {
+	for _, layer := range NamingLayers {
+		if n.InterfaceName("user", layer) != "User"+capitalize(layer) ||
+			n.StructName("user", layer) != "user"+capitalize(layer) ||
+			n.ConstructorName("user", layer) != "NewUser"+capitalize(layer) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate reports templates that do not derive valid Go names: interfaces
+// and constructors must be exported, structs unexported, and every template
+// must name the domain
+func (n Naming) Validate() error {
+	for layer, suffix := range n.Suffixes {
+		if !slices.Contains(NamingLayers, layer) {
+			return fmt.Errorf("naming: unknown layer %q in suffixes (expected %s)", layer, strings.Join(NamingLayers, ", "))
+		}
+		if !token.IsIdentifier(suffix) || !token.IsExported(suffix) {
+			return fmt.Errorf("naming: suffix %q of %s is not an exported Go name", suffix, layer)
+		}
+	}
+
+	templates := []struct {
+		key, template string
+		exported      bool
+	}{
+		{"interface", n.Interface, true},
+		{"struct", n.Struct, false},
+		{"constructor", n.Constructor, true},
+	}
+	for _, t := range templates {
+		if t.template == "" {
+			continue
+		}
+		if !strings.Contains(t.template, "{Domain}") && !strings.Contains(t.template, "{domain}") {
+			return fmt.Errorf("naming: %s template %q does not contain {Domain} or {domain}", t.key, t.template)
+		}
+		for _, layer := range NamingLayers {
+			name := n.expand(t.template, "user", layer)
+			if !token.IsIdentifier(name) {
+				return fmt.Errorf("naming: %s template %q derives %q, which is not a Go name", t.key, t.template, name)
+			}
+			if token.IsExported(name) != t.exported {
+				visibility := "unexported"
+				if t.exported {
+					visibility = "exported"
+				}
+				return fmt.Errorf("naming: %s template %q derives %q, which is not %s", t.key, t.template, name, visibility)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Suffix returns the {Layer} of a layer's names, e.g. Service
+func (n Naming) Suffix(layer string) string {
+	if suffix := n.Suffixes[layer]; suffix != "" {
+		return suffix
+	}
+	return capitalize(layer)
+}
+
+// InterfaceName returns the name of the exported interface of a domain's layer
+func (n Naming) InterfaceName(domain, layer string) string {
+	return n.expand(orDefault(n.Interface, defaultInterfaceTemplate), domain, layer)
+}
+
+// StructName returns the name of the unexported struct implementing a
+// domain's layer
+func (n Naming) StructName(domain, layer string) string {
+	return n.expand(orDefault(n.Struct, defaultStructTemplate), domain, layer)
+}
+
+// ConstructorName returns the name of the constructor of a domain's layer
+func (n Naming) ConstructorName(domain, layer string) string {
+	return n.expand(orDefault(n.Constructor, defaultConstructorTemplate), domain, layer)
+}
+
+func (n Naming) expand(template, domain, layer string) string {
+	return strings.NewReplacer(
+		"{Domain}", capitalize(domain),
+		"{domain}", domain,
+		"{Layer}", n.Suffix(layer),
+	).Replace(template)
+}
+
+// match reports whether name was derived from template for a layer,
+// returning the domain it was derived from. {domain} also matches exported
+// names, so structs exported by mistake are still recognized.
+func (n Naming) match(template, name, layer string) (string, bool) {
+	pattern := strings.NewReplacer(
+		`\{Domain\}`, `([A-Z][A-Za-z0-9]*)`,
+		`\{domain\}`, `([A-Za-z][A-Za-z0-9]*)`,
+		`\{Layer\}`, regexp.QuoteMeta(n.Suffix(layer)),
+	).Replace(regexp.QuoteMeta(template))
+	groups := regexp.MustCompile("^" + pattern + "$").FindStringSubmatch(name)
+	if groups == nil {
+		return "", false
+	}
+	return strings.ToLower(groups[1][:1]) + groups[1][1:], true
+}
+
+// isLayerType reports whether a type name is an interface or struct of the
+// layer: named with the layer's suffix or derived from the templates
+func (n Naming) isLayerType(name, layer string) bool {
+	if strings.HasSuffix(name, n.Suffix(layer)) {
+		return true
+	}
+	_, isInterface := n.match(orDefault(n.Interface, defaultInterfaceTemplate), name, layer)
+	_, isStruct := n.match(orDefault(n.Struct, defaultStructTemplate), name, layer)
+	return isInterface || isStruct
+}
+
+// interfaceFor returns the interface a struct or interface of the layer
+// should be exposed through, e.g. UserRepository for userRepository
+func (n Naming) interfaceFor(name, layer string) string {
+	for _, template := range []string{orDefault(n.Struct, defaultStructTemplate), orDefault(n.Interface, defaultInterfaceTemplate)} {
+		if domain, ok := n.match(template, name, layer); ok {
+			return n.InterfaceName(domain, layer)
+		}
+	}
+	return capitalize(name)
+}
+
+// isConstructor reports whether a function name is a constructor: New*, or
+// derived from the constructor template
+func (n Naming) isConstructor(name string) bool {
+	if strings.HasPrefix(name, "New") {
+		return true
+	}
+	for _, layer := range NamingLayers {
+		if _, ok := n.match(orDefault(n.Constructor, defaultConstructorTemplate), name, layer); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// orDefault returns template, or fallback when it is empty
+func orDefault(template, fallback string) string {
+	if template == "" {
+		return fallback
+	}
+	return template
+}